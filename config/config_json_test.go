@@ -1720,6 +1720,7 @@ func TestDfDispatcherSJsonCfg(t *testing.T) {
 		Prefix_indexed_fields: &[]string{},
 		Attributes_conns:      &[]string{},
 		Nested_fields:         utils.BoolPointer(false),
+		Self_host_id:          utils.StringPointer(""),
 	}
 	if cfg, err := dfCgrJsonCfg.DispatcherSJsonCfg(); err != nil {
 		t.Error(err)
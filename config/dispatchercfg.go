@@ -32,6 +32,7 @@ type DispatcherSCfg struct {
 	PrefixIndexedFields *[]string
 	AttributeSConns     []string
 	NestedFields        bool
+	SelfHostID          string // host of this engine, dispatched to only if no other host answers
 }
 
 func (dps *DispatcherSCfg) loadFromJsonCfg(jsnCfg *DispatcherSJsonCfg) (err error) {
@@ -72,6 +73,9 @@ func (dps *DispatcherSCfg) loadFromJsonCfg(jsnCfg *DispatcherSJsonCfg) (err erro
 	if jsnCfg.Nested_fields != nil {
 		dps.NestedFields = *jsnCfg.Nested_fields
 	}
+	if jsnCfg.Self_host_id != nil {
+		dps.SelfHostID = *jsnCfg.Self_host_id
+	}
 	return nil
 }
 
@@ -107,6 +111,7 @@ func (dps *DispatcherSCfg) AsMapInterface() map[string]interface{} {
 		utils.PrefixIndexedFieldsCfg: prefixIndexedFields,
 		utils.AttributeSConnsCfg:     attributeSConns,
 		utils.NestedFieldsCfg:        dps.NestedFields,
+		utils.SelfHostIDCfg:          dps.SelfHostID,
 	}
 
 }
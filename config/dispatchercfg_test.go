@@ -96,6 +96,7 @@ func TestDispatcherSCfgAsMapInterface(t *testing.T) {
 		"nested_fields":         false,
 		"attributes_conns":      []string{},
 		"string_indexed_fields": []string{},
+		"self_host_id":          "",
 	}
 	if jsnCfg, err := NewCgrJsonCfgFromBytes([]byte(cfgJSONStr)); err != nil {
 		t.Error(err)
@@ -115,6 +116,7 @@ func TestDispatcherSCfgAsMapInterface(t *testing.T) {
 			"prefix_indexed_fields": ["prefix","indexed","fields"],
 			"nested_fields": false,
 			"attributes_conns": ["*internal"],
+			"self_host_id": "DSP_SELF",
 		},
 		
 }`
@@ -125,6 +127,7 @@ func TestDispatcherSCfgAsMapInterface(t *testing.T) {
 		"nested_fields":         false,
 		"attributes_conns":      []string{"*internal"},
 		"string_indexed_fields": []string{"string", "indexed", "fields"},
+		"self_host_id":          "DSP_SELF",
 	}
 	if jsnCfg, err := NewCgrJsonCfgFromBytes([]byte(cfgJSONStr)); err != nil {
 		t.Error(err)
@@ -532,6 +532,7 @@ type DispatcherSJsonCfg struct {
 	Prefix_indexed_fields *[]string
 	Nested_fields         *bool // applies when indexed fields is not defined
 	Attributes_conns      *[]string
+	Self_host_id          *string
 }
 
 type LoaderCfgJson struct {
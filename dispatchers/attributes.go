@@ -39,11 +39,7 @@ func (dS *DispatcherService) AttributeSv1Ping(args *utils.CGREventWithArgDispatc
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(args.CGREvent, utils.MetaAttributes, routeID,
+	return dS.Dispatch(args.CGREvent, utils.MetaAttributes, args.ArgDispatcher,
 		utils.AttributeSv1Ping, args, reply)
 }
 
@@ -63,11 +59,7 @@ func (dS *DispatcherService) AttributeSv1GetAttributeForEvent(args *engine.AttrA
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(args.CGREvent, utils.MetaAttributes, routeID,
+	return dS.Dispatch(args.CGREvent, utils.MetaAttributes, args.ArgDispatcher,
 		utils.AttributeSv1GetAttributeForEvent, args, reply)
 }
 
@@ -87,10 +79,6 @@ func (dS *DispatcherService) AttributeSv1ProcessEvent(args *engine.AttrArgsProce
 		}
 
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(args.CGREvent, utils.MetaAttributes, routeID,
+	return dS.Dispatch(args.CGREvent, utils.MetaAttributes, args.ArgDispatcher,
 		utils.AttributeSv1ProcessEvent, args, reply)
 }
@@ -41,11 +41,7 @@ func (dS *DispatcherService) CacheSv1Ping(args *utils.CGREventWithArgDispatcher,
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(args.CGREvent, utils.MetaCaches, routeID,
+	return dS.Dispatch(args.CGREvent, utils.MetaCaches, args.ArgDispatcher,
 		utils.CacheSv1Ping, args, reply)
 }
 
@@ -65,11 +61,7 @@ func (dS *DispatcherService) CacheSv1GetItemIDs(args *utils.ArgsGetCacheItemIDsW
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaCaches, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaCaches, args.ArgDispatcher,
 		utils.CacheSv1GetItemIDs, args, reply)
 }
 
@@ -89,11 +81,7 @@ func (dS *DispatcherService) CacheSv1HasItem(args *utils.ArgsGetCacheItemWithArg
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaCaches, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaCaches, args.ArgDispatcher,
 		utils.CacheSv1HasItem, args, reply)
 }
 
@@ -113,11 +101,7 @@ func (dS *DispatcherService) CacheSv1GetItemExpiryTime(args *utils.ArgsGetCacheI
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaCaches, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaCaches, args.ArgDispatcher,
 		utils.CacheSv1GetItemExpiryTime, args, reply)
 }
 
@@ -137,11 +121,7 @@ func (dS *DispatcherService) CacheSv1RemoveItem(args *utils.ArgsGetCacheItemWith
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaCaches, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaCaches, args.ArgDispatcher,
 		utils.CacheSv1RemoveItem, args, reply)
 }
 
@@ -161,11 +141,7 @@ func (dS *DispatcherService) CacheSv1Clear(args *utils.AttrCacheIDsWithArgDispat
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaCaches, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaCaches, args.ArgDispatcher,
 		utils.CacheSv1Clear, args, reply)
 }
 
@@ -185,11 +161,7 @@ func (dS *DispatcherService) CacheSv1GetCacheStats(args *utils.AttrCacheIDsWithA
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaCaches, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaCaches, args.ArgDispatcher,
 		utils.CacheSv1GetCacheStats, args, reply)
 }
 
@@ -208,11 +180,7 @@ func (dS *DispatcherService) CacheSv1PrecacheStatus(args *utils.AttrCacheIDsWith
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaCaches, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaCaches, args.ArgDispatcher,
 		utils.CacheSv1PrecacheStatus, args, reply)
 }
 
@@ -232,11 +200,7 @@ func (dS *DispatcherService) CacheSv1HasGroup(args *utils.ArgsGetGroupWithArgDis
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaCaches, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaCaches, args.ArgDispatcher,
 		utils.CacheSv1HasGroup, args, reply)
 }
 
@@ -256,11 +220,7 @@ func (dS *DispatcherService) CacheSv1GetGroupItemIDs(args *utils.ArgsGetGroupWit
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaCaches, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaCaches, args.ArgDispatcher,
 		utils.CacheSv1GetGroupItemIDs, args, reply)
 }
 
@@ -279,11 +239,7 @@ func (dS *DispatcherService) CacheSv1RemoveGroup(args *utils.ArgsGetGroupWithArg
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaCaches, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaCaches, args.ArgDispatcher,
 		utils.CacheSv1RemoveGroup, args, reply)
 }
 
@@ -302,11 +258,7 @@ func (dS *DispatcherService) CacheSv1ReloadCache(args utils.AttrReloadCacheWithA
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaCaches, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaCaches, args.ArgDispatcher,
 		utils.CacheSv1ReloadCache, args, reply)
 }
 
@@ -325,11 +277,7 @@ func (dS *DispatcherService) CacheSv1LoadCache(args utils.AttrReloadCacheWithArg
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaCaches, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaCaches, args.ArgDispatcher,
 		utils.CacheSv1LoadCache, args, reply)
 }
 
@@ -348,11 +296,7 @@ func (dS *DispatcherService) CacheSv1ReplicateRemove(args *utils.ArgCacheReplica
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaCaches, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaCaches, args.ArgDispatcher,
 		utils.CacheSv1ReplicateRemove, args, reply)
 }
 
@@ -371,10 +315,6 @@ func (dS *DispatcherService) CacheSv1ReplicateSet(args *utils.ArgCacheReplicateS
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaCaches, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaCaches, args.ArgDispatcher,
 		utils.CacheSv1ReplicateSet, args, reply)
 }
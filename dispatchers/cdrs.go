@@ -44,11 +44,7 @@ func (dS *DispatcherService) CDRsV1Ping(args *utils.CGREventWithArgDispatcher,
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(args.CGREvent, utils.MetaCDRs, routeID,
+	return dS.Dispatch(args.CGREvent, utils.MetaCDRs, args.ArgDispatcher,
 		utils.CDRsV1Ping, args, reply)
 }
 
@@ -66,11 +62,7 @@ func (dS *DispatcherService) CDRsV1GetCDRs(args utils.RPCCDRsFilterWithArgDispat
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaCDRs, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaCDRs, args.ArgDispatcher,
 		utils.CDRsV1GetCDRs, args, reply)
 }
 
@@ -88,11 +80,7 @@ func (dS *DispatcherService) CDRsV1GetCDRsCount(args *utils.RPCCDRsFilterWithArg
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaCDRs, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaCDRs, args.ArgDispatcher,
 		utils.CDRsV1GetCDRsCount, args, reply)
 }
 
@@ -110,11 +98,7 @@ func (dS *DispatcherService) CDRsV1StoreSessionCost(args *engine.AttrCDRSStoreSM
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaCDRs, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaCDRs, args.ArgDispatcher,
 		utils.CDRsV1StoreSessionCost, args, reply)
 }
 
@@ -132,11 +116,7 @@ func (dS *DispatcherService) CDRsV1RateCDRs(args *engine.ArgRateCDRs, reply *str
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaCDRs, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaCDRs, args.ArgDispatcher,
 		utils.CDRsV1RateCDRs, args, reply)
 }
 
@@ -154,11 +134,7 @@ func (dS *DispatcherService) CDRsV1ProcessExternalCDR(args *engine.ExternalCDRWi
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaCDRs, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaCDRs, args.ArgDispatcher,
 		utils.CDRsV1ProcessExternalCDR, args, reply)
 }
 
@@ -176,11 +152,7 @@ func (dS *DispatcherService) CDRsV1ProcessEvent(args *engine.ArgV1ProcessEvent,
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&args.CGREvent, utils.MetaCDRs, routeID,
+	return dS.Dispatch(&args.CGREvent, utils.MetaCDRs, args.ArgDispatcher,
 		utils.CDRsV1ProcessEvent, args, reply)
 }
 
@@ -198,11 +170,7 @@ func (dS *DispatcherService) CDRsV1ProcessCDR(args *engine.CDRWithOpts, reply *s
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaCDRs, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaCDRs, args.ArgDispatcher,
 		utils.CDRsV1ProcessCDR, args, reply)
 }
 
@@ -220,11 +188,7 @@ func (dS *DispatcherService) CDRsV2ProcessEvent(args *engine.ArgV1ProcessEvent,
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&args.CGREvent, utils.MetaCDRs, routeID,
+	return dS.Dispatch(&args.CGREvent, utils.MetaCDRs, args.ArgDispatcher,
 		utils.CDRsV2ProcessEvent, args, reply)
 }
 
@@ -242,10 +206,6 @@ func (dS *DispatcherService) CDRsV2StoreSessionCost(args *engine.ArgsV2CDRSStore
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaCDRs, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaCDRs, args.ArgDispatcher,
 		utils.CDRsV2StoreSessionCost, args, reply)
 }
@@ -37,11 +37,7 @@ func (dS *DispatcherService) ChargerSv1Ping(args *utils.CGREventWithArgDispatche
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(args.CGREvent, utils.MetaChargers, routeID,
+	return dS.Dispatch(args.CGREvent, utils.MetaChargers, args.ArgDispatcher,
 		utils.ChargerSv1Ping, args, reply)
 }
 
@@ -60,11 +56,7 @@ func (dS *DispatcherService) ChargerSv1GetChargersForEvent(args *utils.CGREventW
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(args.CGREvent, utils.MetaChargers, routeID,
+	return dS.Dispatch(args.CGREvent, utils.MetaChargers, args.ArgDispatcher,
 		utils.ChargerSv1GetChargersForEvent, args, reply)
 }
 
@@ -83,10 +75,6 @@ func (dS *DispatcherService) ChargerSv1ProcessEvent(args *utils.CGREventWithOpts
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(args.CGREvent, utils.MetaChargers, routeID,
+	return dS.Dispatch(args.CGREvent, utils.MetaChargers, args.ArgDispatcher,
 		utils.ChargerSv1ProcessEvent, args, reply)
 }
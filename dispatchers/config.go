@@ -39,12 +39,8 @@ func (dS *DispatcherService) ConfigSv1GetJSONSection(args *config.StringWithArgD
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
 	return dS.Dispatch(&utils.CGREvent{Tenant: tnt},
-		utils.MetaConfig, routeID, utils.ConfigSv1GetJSONSection, args, reply)
+		utils.MetaConfig, args.ArgDispatcher, utils.ConfigSv1GetJSONSection, args, reply)
 }
 
 func (dS *DispatcherService) ConfigSv1ReloadConfigFromPath(args *config.ConfigReloadWithArgDispatcher, reply *string) (err error) {
@@ -61,12 +57,8 @@ func (dS *DispatcherService) ConfigSv1ReloadConfigFromPath(args *config.ConfigRe
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
 	return dS.Dispatch(&utils.CGREvent{Tenant: tnt},
-		utils.MetaConfig, routeID, utils.ConfigSv1ReloadConfigFromPath, args, reply)
+		utils.MetaConfig, args.ArgDispatcher, utils.ConfigSv1ReloadConfigFromPath, args, reply)
 }
 
 func (dS *DispatcherService) ConfigSv1ReloadConfigFromJSON(args *config.JSONReloadWithArgDispatcher, reply *string) (err error) {
@@ -83,10 +75,6 @@ func (dS *DispatcherService) ConfigSv1ReloadConfigFromJSON(args *config.JSONRelo
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
 	return dS.Dispatch(&utils.CGREvent{Tenant: tnt},
-		utils.MetaConfig, routeID, utils.ConfigSv1ReloadConfigFromJSON, args, reply)
+		utils.MetaConfig, args.ArgDispatcher, utils.ConfigSv1ReloadConfigFromJSON, args, reply)
 }
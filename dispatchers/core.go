@@ -39,12 +39,8 @@ func (dS *DispatcherService) CoreSv1Status(args *utils.TenantWithArgDispatcher,
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
 	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaCore,
-		routeID, utils.CoreSv1Status, args, reply)
+		args.ArgDispatcher, utils.CoreSv1Status, args, reply)
 }
 
 func (dS *DispatcherService) CoreSv1Ping(args *utils.CGREventWithArgDispatcher, reply *string) (err error) {
@@ -61,10 +57,6 @@ func (dS *DispatcherService) CoreSv1Ping(args *utils.CGREventWithArgDispatcher,
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(args.CGREvent, utils.MetaCore, routeID,
+	return dS.Dispatch(args.CGREvent, utils.MetaCore, args.ArgDispatcher,
 		utils.CoreSv1Ping, args, reply)
 }
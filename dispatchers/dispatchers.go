@@ -37,7 +37,7 @@ func NewDispatcherService(dm *engine.DataManager,
 	connMgr *engine.ConnManager) (*DispatcherService, error) {
 
 	return &DispatcherService{dm: dm, cfg: cfg,
-		fltrS: fltrS, connMgr: connMgr}, nil
+		fltrS: fltrS, connMgr: connMgr, audit: new(auditHook)}, nil
 }
 
 // DispatcherService  is the service handling dispatching towards internal components
@@ -47,6 +47,15 @@ type DispatcherService struct {
 	cfg     *config.CGRConfig
 	fltrS   *engine.FilterS
 	connMgr *engine.ConnManager
+	audit   *auditHook // shared by the dispatchers built by the service
+}
+
+// SetAuditHook sets the function receiving one AuditRecord per routing decision of the *weight, *random,
+// *round_robin, *ratio and *load strategies, the broadcast ones having no single chosen host.
+// It can be changed at any time and nil disables the auditing. The hook is called outside
+// the dispatcher locks but on the path of the call so it should not block
+func (dS *DispatcherService) SetAuditHook(hook func(rec AuditRecord)) {
+	dS.audit.set(hook)
 }
 
 // ListenAndServe will initialize the service
@@ -169,7 +178,7 @@ func (dS *DispatcherService) dispatcherProfileForEvent(ev *utils.CGREvent,
 }
 
 // Dispatch is the method forwarding the request towards the right connection
-func (dS *DispatcherService) Dispatch(ev *utils.CGREvent, subsys string, routeID *string,
+func (dS *DispatcherService) Dispatch(ev *utils.CGREvent, subsys string, argD *utils.ArgDispatcher,
 	serviceMethod string, args interface{}, reply interface{}) (err error) {
 	dPrfl, errDsp := dS.dispatcherProfileForEvent(ev, subsys)
 	if errDsp != nil {
@@ -181,13 +190,15 @@ func (dS *DispatcherService) Dispatch(ev *utils.CGREvent, subsys string, routeID
 	if x, ok := engine.Cache.Get(utils.CacheDispatchers,
 		tntID); ok && x != nil {
 		d = x.(Dispatcher)
-	} else if d, err = newDispatcher(dS.dm, dPrfl); err != nil {
+	} else if d, err = newAuditedDispatcher(dS.dm, dPrfl, dS.audit); err != nil {
+		return utils.NewErrDispatcherS(err)
+	} else if err = d.Validate(); err != nil {
 		return utils.NewErrDispatcherS(err)
 	}
 	if errCh := engine.Cache.Set(utils.CacheDispatchers, tntID, d, nil, true, utils.EmptyString); errCh != nil {
 		return utils.NewErrDispatcherS(errCh)
 	}
-	return d.Dispatch(routeID, subsys, serviceMethod, args, reply)
+	return d.Dispatch(ev, argD, subsys, serviceMethod, args, reply)
 }
 
 func (dS *DispatcherService) V1GetProfileForEvent(ev *DispatcherEvent,
@@ -280,11 +291,7 @@ func (dS *DispatcherService) V1Apier(apier interface{}, args *utils.MethodParame
 		realArgs = reflect.ValueOf(realArgs).Elem().Interface()
 	}
 
-	var routeID *string
-	if argD != nil {
-		routeID = argD.RouteID
-	}
-	if err := dS.Dispatch(&utils.CGREvent{Tenant: tenant, Event: parameters}, utils.MetaApier, routeID,
+	if err := dS.Dispatch(&utils.CGREvent{Tenant: tenant, Event: parameters}, utils.MetaApier, argD,
 		args.Method, realArgs, realReply); err != nil {
 		return err
 	}
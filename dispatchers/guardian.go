@@ -41,11 +41,7 @@ func (dS *DispatcherService) GuardianSv1Ping(args *utils.CGREventWithArgDispatch
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(args.CGREvent, utils.MetaGuardian, routeID,
+	return dS.Dispatch(args.CGREvent, utils.MetaGuardian, args.ArgDispatcher,
 		utils.GuardianSv1Ping, args, reply)
 }
 
@@ -65,11 +61,7 @@ func (dS *DispatcherService) GuardianSv1RemoteLock(args AttrRemoteLockWithApiKey
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaGuardian, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaGuardian, args.ArgDispatcher,
 		utils.GuardianSv1RemoteLock, args, reply)
 }
 
@@ -89,10 +81,6 @@ func (dS *DispatcherService) GuardianSv1RemoteUnlock(args AttrRemoteUnlockWithAp
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaGuardian, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaGuardian, args.ArgDispatcher,
 		utils.GuardianSv1RemoteUnlock, args, reply)
 }
@@ -21,10 +21,18 @@ package dispatchers
 import (
 	"encoding/gob"
 	"fmt"
+	"hash/fnv"
+	"math"
+	"math/big"
+	"math/rand"
+	"reflect"
 	"sort"
 	"strconv"
 	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/cgrates/cgrates/config"
 	"github.com/cgrates/cgrates/engine"
 	"github.com/cgrates/cgrates/utils"
 )
@@ -36,69 +44,177 @@ func init() {
 
 // Dispatcher is responsible for routing requests to pool of connections
 // there will be different implementations based on strategy
-
-// Dispatcher is responsible for routing requests to pool of connections
-// there will be different implementations based on strategy
+//
+// The dispatchers keep a deep copy of the profile hosts (see engine.DispatcherHostProfiles.Clone),
+// taken on construction and on SetProfile, so neither the profile nor the
+// ordered copies returned by HostIDs share mutable state with them.
+// A host ID listed more than once is only kept on its first occurrence after sorting,
+// the best priority and weight one, the others being logged and ignored.
+// The only runtime state deliberately shared is the *load LoadMetrics, kept in
+// the *dispatcher_loads cache for all the dispatchers of the same profile and guarded by its own mutex.
 type Dispatcher interface {
 	// SetProfile is used to update the configuration information within dispatcher
 	// to make sure we take decisions based on latest config
 	SetProfile(pfl *engine.DispatcherProfile)
+	// Validate checks the consistency of the dispatcher before it is used
+	Validate() (err error)
+	// Profile returns a copy of the profile the dispatcher was last configured with
+	Profile() (pfl *engine.DispatcherProfile)
+	// ProfileHash returns a content hash of the profile the dispatcher was last configured with
+	ProfileHash() (hash string)
 	// HostIDs returns the ordered list of host IDs
 	HostIDs() (hostIDs []string)
+	// WithHosts calls fn with the sorted hosts under the read lock, without copying them
+	// fn must not modify or keep the hosts and must not call the dispatcher
+	WithHosts(fn func(hosts engine.DispatcherHostProfiles))
+	// SelectionLatency returns the percentiles of the time spent by the dispatcher
+	// to order the hosts, all zero if the recording is not enabled
+	SelectionLatency() (p50, p95, p99 time.Duration)
+	// Degradations returns how many times the strategy fell back to a simpler behavior, per reason
+	Degradations() (dgs map[string]int64)
+	// FailoverDepthDistribution returns the number of answered calls per position of the answering host
+	// in the failover order, 0 being the first host
+	FailoverDepthDistribution() (depths map[int]int)
+	// Status returns the overall state of the dispatcher in one call
+	Status() (st DispatcherStatus)
 	// Dispatch is used to send the method over the connections given
-	Dispatch(routeID *string, subsystem,
+	// the event is the one the dispatcher profile was matched for,
+	// the Strategy of the ArgDispatcher can change the hosts order for this call only,
+	// while the *kill_switch strategy param bypasses the strategy for all the calls.
+	// With *broadcast_async the hosts are called one after the other in background and Dispatch returns without waiting,
+	// setting a *string reply to OK and leaving the other replies unchanged
+	Dispatch(ev *utils.CGREvent, argD *utils.ArgDispatcher, subsystem,
 		serviceMethod string, args interface{}, reply interface{}) (err error)
 }
 
 type strategyDispatcher interface {
 	// dispatch is used to send the method over the connections given
-	dispatch(dm *engine.DataManager, routeID *string, subsystem, tnt string, hostIDs []string,
+	dispatch(dm *engine.DataManager, ev *utils.CGREvent, routeID *string, subsystem, tnt string, hostIDs []string,
 		serviceMethod string, args interface{}, reply interface{}) (err error)
 }
 
 // newDispatcher constructs instances of Dispatcher
 func newDispatcher(dm *engine.DataManager, pfl *engine.DispatcherProfile) (d Dispatcher, err error) {
-	pfl.Hosts.Sort() // make sure the connections are sorted
+	return newAuditedDispatcher(dm, pfl, nil)
+}
+
+// newAuditedDispatcher constructs instances of Dispatcher passing their routing decisions to the audit hook
+func newAuditedDispatcher(dm *engine.DataManager, pfl *engine.DispatcherProfile,
+	ah *auditHook) (d Dispatcher, err error) {
+	var preserve bool
+	if preserve, err = preserveOrder(pfl.StrategyParams); err != nil {
+		return
+	}
+	if !preserve {
+		pfl.Hosts.Sort() // make sure the connections are sorted
+	} else {
+		sortHostsByPriority(pfl.Hosts)
+	}
+	hosts := dispatcherHosts(pfl)
+	var sl *selectionLatency
+	if sl, err = newSelectionLatency(pfl.StrategyParams); err != nil {
+		return
+	}
+	dgs := newStrategyDegradations(pfl.TenantID(), pfl.Strategy)
+	dp := newDispatcherProfile(pfl)
+	dp.auditHook = ah
+	fd := newFailoverDepths()
 	switch pfl.Strategy {
 	case utils.MetaWeight:
-		d = &WeightDispatcher{
-			dm:       dm,
-			tnt:      pfl.Tenant,
-			hosts:    pfl.Hosts.Clone(),
-			strategy: new(singleResultstrategyDispatcher),
+		wd := &WeightDispatcher{
+			dm:                   dm,
+			tnt:                  pfl.Tenant,
+			hosts:                hosts,
+			strategy:             &singleResultstrategyDispatcher{depths: fd, dp: dp},
+			selectionLatency:     sl,
+			strategyDegradations: dgs,
+			dispatcherProfile:    dp,
+			failoverDepths:       fd,
+		}
+		if wd.tieBreak, err = newTieBreaker(pfl.StrategyParams); err != nil {
+			return nil, err
 		}
+		d = wd
 	case utils.MetaRandom:
-		d = &RandomDispatcher{
-			dm:       dm,
-			tnt:      pfl.Tenant,
-			hosts:    pfl.Hosts.Clone(),
-			strategy: new(singleResultstrategyDispatcher),
+		rd := &RandomDispatcher{
+			dm:                   dm,
+			tnt:                  pfl.Tenant,
+			hosts:                hosts,
+			strategy:             &singleResultstrategyDispatcher{depths: fd, dp: dp},
+			selectionLatency:     sl,
+			strategyDegradations: dgs,
+			dispatcherProfile:    dp,
+			failoverDepths:       fd,
+		}
+		if rd.idSeed, err = eventIDSeed(pfl.StrategyParams); err != nil {
+			return nil, err
+		}
+		if rd.antiAff, err = antiAffinity(pfl.StrategyParams); err != nil {
+			return nil, err
 		}
+		d = rd
 	case utils.MetaRoundRobin:
 		d = &RoundRobinDispatcher{
-			dm:       dm,
-			tnt:      pfl.Tenant,
-			hosts:    pfl.Hosts.Clone(),
-			strategy: new(singleResultstrategyDispatcher),
+			dm:                   dm,
+			tnt:                  pfl.Tenant,
+			hosts:                hosts,
+			strategy:             &singleResultstrategyDispatcher{depths: fd, dp: dp},
+			selectionLatency:     sl,
+			strategyDegradations: dgs,
+			dispatcherProfile:    dp,
+			failoverDepths:       fd,
+		}
+	case utils.MetaRatio:
+		rd := &RatioDispatcher{
+			dm:                   dm,
+			tnt:                  pfl.Tenant,
+			hosts:                hosts,
+			strategy:             &singleResultstrategyDispatcher{depths: fd, dp: dp},
+			selectionLatency:     sl,
+			strategyDegradations: dgs,
+			dispatcherProfile:    dp,
+			failoverDepths:       fd,
+		}
+		if rd.cycles, rd.cycleLen, err = ratioCycles(hosts, pfl.StrategyParams); err != nil {
+			return nil, err
 		}
-	case utils.MetaBroadcast:
+		d = rd
+	case utils.MetaBroadcast, utils.MetaBroadcastSync, utils.MetaBroadcastAsync:
+		bs, err := newBrodcastStrategyDispatcher(pfl.Strategy, pfl.StrategyParams)
+		if err != nil {
+			return nil, err
+		}
+		bs.degradations = dgs
 		d = &BroadcastDispatcher{
-			dm:       dm,
-			tnt:      pfl.Tenant,
-			hosts:    pfl.Hosts.Clone(),
-			strategy: new(brodcastStrategyDispatcher),
+			dm:                   dm,
+			tnt:                  pfl.Tenant,
+			hosts:                hosts,
+			strategy:             bs,
+			selectionLatency:     sl,
+			strategyDegradations: dgs,
+			dispatcherProfile:    dp,
+			failoverDepths:       fd,
 		}
 	case utils.MetaLoad:
-		hosts := pfl.Hosts.Clone()
 		ls, err := newLoadStrategyDispatcher(hosts, pfl.TenantID())
 		if err != nil {
 			return nil, err
 		}
+		ls.degradations = dgs
+		ls.depths = fd
+		ls.dp = dp
+		if ls.tieBreak, err = newTieBreaker(pfl.StrategyParams); err != nil {
+			return nil, err
+		}
 		d = &WeightDispatcher{
-			dm:       dm,
-			tnt:      pfl.Tenant,
-			hosts:    hosts,
-			strategy: ls,
+			dm:                   dm,
+			tnt:                  pfl.Tenant,
+			hosts:                hosts,
+			strategy:             ls,
+			selectionLatency:     sl,
+			strategyDegradations: dgs,
+			dispatcherProfile:    dp,
+			failoverDepths:       fd,
 		}
 	default:
 		err = fmt.Errorf("unsupported dispatch strategy: <%s>", pfl.Strategy)
@@ -112,27 +228,82 @@ type WeightDispatcher struct {
 	dm       *engine.DataManager
 	tnt      string
 	hosts    engine.DispatcherHostProfiles
+	tieBreak *tieBreaker // orders the hosts with equal weight, nil keeps the sorting order
 	strategy strategyDispatcher
+	*selectionLatency
+	*strategyDegradations
+	*dispatcherProfile
+	*failoverDepths
 }
 
 func (wd *WeightDispatcher) SetProfile(pfl *engine.DispatcherProfile) {
+	if wd.unchangedProfile(pfl) { // keep the strategy state
+		return
+	}
+	tb, err := newTieBreaker(pfl.StrategyParams)
+	if err != nil {
+		utils.Logger.Warning(fmt.Sprintf("<%s> error: <%s> updating %s strategy for profile %q, keeping previous strategy params",
+			utils.DispatcherS, err.Error(), utils.MetaWeight, pfl.TenantID()))
+	}
 	wd.Lock()
-	pfl.Hosts.Sort()
-	wd.hosts = pfl.Hosts.Clone() // avoid concurrency on profile
+	sortProfileHosts(pfl)
+	wd.hosts = dispatcherHosts(pfl) // avoid concurrency on profile
+	if ls, isLoad := wd.strategy.(*loadStrategyDispatcher); isLoad {
+		ls.setProfile(wd.hosts, tb, err == nil)
+	} else if err == nil && wd.tieBreak.policy() != tb.policy() {
+		wd.tieBreak = tb
+	}
 	wd.Unlock()
+	wd.setProfile(pfl)
+	return
+}
+
+func (wd *WeightDispatcher) Validate() (err error) {
+	if err = wd.validateKillSwitch(); err != nil {
+		return
+	}
+	wd.RLock()
+	defer wd.RUnlock()
+	if err = validateHosts(wd.hosts); err != nil {
+		return
+	}
+	if ls, isLoad := wd.strategy.(*loadStrategyDispatcher); isLoad {
+		ls.RLock()
+		lsHosts := len(ls.hosts)
+		ls.RUnlock()
+		if lsHosts != len(wd.hosts) {
+			return fmt.Errorf("%s strategy has %d hosts instead of %d", utils.MetaLoad, lsHosts, len(wd.hosts))
+		}
+	}
 	return
 }
 
 func (wd *WeightDispatcher) HostIDs() (hostIDs []string) {
 	wd.RLock()
-	hostIDs = wd.hosts.HostIDs()
+	policy, rrIdx := wd.tieBreak.next()
+	hostIDs = weightHostIDs(wd.hosts, policy, rrIdx)
 	wd.RUnlock()
 	return
 }
 
-func (wd *WeightDispatcher) Dispatch(routeID *string, subsystem,
+func (wd *WeightDispatcher) Status() DispatcherStatus {
+	return wd.status(wd.strategyDegradations)
+}
+
+func (wd *WeightDispatcher) WithHosts(fn func(hosts engine.DispatcherHostProfiles)) {
+	wd.RLock()
+	fn(wd.hosts)
+	wd.RUnlock()
+}
+
+func (wd *WeightDispatcher) Dispatch(ev *utils.CGREvent, argD *utils.ArgDispatcher, subsystem,
 	serviceMethod string, args interface{}, reply interface{}) (err error) {
-	return wd.strategy.dispatch(wd.dm, routeID, subsystem, wd.tnt, wd.HostIDs(),
+	routeID, subsystem, hostIDs, err := wd.selectHosts(wd.strategyDegradations, wd.selectionLatency,
+		ev, argD, subsystem, wd.HostIDs)
+	if err != nil {
+		return
+	}
+	return wd.strategy.dispatch(wd.dm, ev, routeID, subsystem, wd.tnt, hostIDs,
 		serviceMethod, args, reply)
 }
 
@@ -143,27 +314,122 @@ type RandomDispatcher struct {
 	dm       *engine.DataManager
 	tnt      string
 	hosts    engine.DispatcherHostProfiles
+	idSeed   bool // derive the randomness out of the event ID so the same event reproduces the same order
+	antiAff  bool // discourage selecting the same first host twice in a row
+	lastID   string
 	strategy strategyDispatcher
+	*selectionLatency
+	*strategyDegradations
+	*dispatcherProfile
+	*failoverDepths
 }
 
 func (d *RandomDispatcher) SetProfile(pfl *engine.DispatcherProfile) {
+	if d.unchangedProfile(pfl) { // keep the strategy state
+		return
+	}
+	idSeed, err := eventIDSeed(pfl.StrategyParams)
+	var antiAff bool
+	if err == nil {
+		antiAff, err = antiAffinity(pfl.StrategyParams)
+	}
+	if err != nil {
+		utils.Logger.Warning(fmt.Sprintf("<%s> error: <%s> updating %s strategy for profile %q, keeping previous strategy params",
+			utils.DispatcherS, err.Error(), utils.MetaRandom, pfl.TenantID()))
+	}
 	d.Lock()
-	d.hosts = pfl.Hosts.Clone()
+	sortProfileHosts(pfl)
+	d.hosts = dispatcherHosts(pfl)
+	if err == nil {
+		d.idSeed = idSeed
+		d.antiAff = antiAff
+	}
 	d.Unlock()
+	d.setProfile(pfl)
 	return
 }
 
+func (d *RandomDispatcher) Validate() (err error) {
+	if err = d.validateKillSwitch(); err != nil {
+		return
+	}
+	d.RLock()
+	defer d.RUnlock()
+	return validateHosts(d.hosts)
+}
+
 func (d *RandomDispatcher) HostIDs() (hostIDs []string) {
+	d.RLock()
+	hosts := d.hosts.Clone()
+	antiAff := d.antiAff
+	d.RUnlock()
+	tiers := hosts.Tiers()
+	for _, tier := range tiers {
+		tier.Shuffle() // randomize the connections inside the priority tier
+	}
+	if antiAff && len(tiers) != 0 {
+		d.avoidLastID(tiers[0])
+	}
+	return hosts.HostIDs()
+}
+
+// avoidLastID shuffles the first tier once more if it starts with the previously selected host
+// so a repeat is less likely but still possible, keeping the overall distribution even
+func (d *RandomDispatcher) avoidLastID(tier engine.DispatcherHostProfiles) {
+	d.Lock()
+	if len(tier) > 1 && tier[0].ID == d.lastID {
+		tier.Shuffle()
+	}
+	d.lastID = tier[0].ID
+	d.Unlock()
+}
+
+// hostIDsForEventID returns the hosts shuffled based on the event ID
+// the same ID will always produce the same order while different IDs spread evenly
+func (d *RandomDispatcher) hostIDsForEventID(evID string) (hostIDs []string) {
 	d.RLock()
 	hosts := d.hosts.Clone()
 	d.RUnlock()
-	hosts.Shuffle() // randomize the connections
+	seed := seedFromID(evID)
+	for _, tier := range hosts.Tiers() {
+		tier.ShuffleWithSeed(seed)
+	}
 	return hosts.HostIDs()
 }
 
-func (d *RandomDispatcher) Dispatch(routeID *string, subsystem,
+// hostIDsForEvent returns the hosts shuffled based on the event ID if *event_id_seed is enabled
+func (d *RandomDispatcher) hostIDsForEvent(ev *utils.CGREvent) (hostIDs []string) {
+	d.RLock()
+	idSeed := d.idSeed
+	d.RUnlock()
+	if !idSeed {
+		return d.HostIDs()
+	}
+	if ev == nil || ev.ID == utils.EmptyString {
+		d.degraded(DegradedMissingEventID)
+		return d.HostIDs()
+	}
+	return d.hostIDsForEventID(ev.ID)
+}
+
+func (d *RandomDispatcher) Status() DispatcherStatus {
+	return d.status(d.strategyDegradations)
+}
+
+func (d *RandomDispatcher) WithHosts(fn func(hosts engine.DispatcherHostProfiles)) {
+	d.RLock()
+	fn(d.hosts)
+	d.RUnlock()
+}
+
+func (d *RandomDispatcher) Dispatch(ev *utils.CGREvent, argD *utils.ArgDispatcher, subsystem,
 	serviceMethod string, args interface{}, reply interface{}) (err error) {
-	return d.strategy.dispatch(d.dm, routeID, subsystem, d.tnt, d.HostIDs(),
+	routeID, subsystem, hostIDs, err := d.selectHosts(d.strategyDegradations, d.selectionLatency,
+		ev, argD, subsystem, func() []string { return d.hostIDsForEvent(ev) })
+	if err != nil {
+		return
+	}
+	return d.strategy.dispatch(d.dm, ev, routeID, subsystem, d.tnt, hostIDs,
 		serviceMethod, args, reply)
 }
 
@@ -175,229 +441,1695 @@ type RoundRobinDispatcher struct {
 	hosts    engine.DispatcherHostProfiles
 	hostIdx  int // used for the next connection
 	strategy strategyDispatcher
+	*selectionLatency
+	*strategyDegradations
+	*dispatcherProfile
+	*failoverDepths
 }
 
 func (d *RoundRobinDispatcher) SetProfile(pfl *engine.DispatcherProfile) {
+	if d.unchangedProfile(pfl) { // keep the strategy state
+		return
+	}
 	d.Lock()
-	d.hosts = pfl.Hosts.Clone()
+	sortProfileHosts(pfl)
+	d.hosts = dispatcherHosts(pfl)
 	d.Unlock()
+	d.setProfile(pfl)
 	return
 }
 
-func (d *RoundRobinDispatcher) HostIDs() (hostIDs []string) {
+func (d *RoundRobinDispatcher) Validate() (err error) {
+	if err = d.validateKillSwitch(); err != nil {
+		return
+	}
 	d.RLock()
-	hosts := d.hosts.Clone()
-	hosts.ReorderFromIndex(d.hostIdx)
-	d.hostIdx++
-	if d.hostIdx >= len(d.hosts) {
-		d.hostIdx = 0
+	defer d.RUnlock()
+	if err = validateHosts(d.hosts); err != nil {
+		return
+	}
+	if d.hostIdx < 0 {
+		return fmt.Errorf("invalid round robin index: %d", d.hostIdx)
+	}
+	return
+}
+
+func (d *RoundRobinDispatcher) HostIDs() (hostIDs []string) {
+	d.Lock()
+	hostIDs, d.hostIdx = roundRobinHostIDs(d.hosts, d.hostIdx)
+	d.Unlock()
+	return
+}
+
+// roundRobinHostIDs returns the sorted hosts with each priority tier rotated to idx and the index
+// of the next selection, without changing the hosts
+func roundRobinHostIDs(hosts engine.DispatcherHostProfiles, idx int) (hostIDs []string, nextIdx int) {
+	hosts = hosts.Clone()
+	cycle := 1 // number of selections after which all the tiers start again from their first host
+	for _, tier := range hosts.Tiers() {
+		tier.ReorderFromIndex(idx % len(tier)) // rotate the hosts inside the priority tier
+		cycle = lcm(cycle, len(tier))
+	}
+	if nextIdx = idx + 1; nextIdx >= cycle {
+		nextIdx = 0
 	}
+	return hosts.HostIDs(), nextIdx
+}
+
+func (d *RoundRobinDispatcher) Status() DispatcherStatus {
+	return d.status(d.strategyDegradations)
+}
+
+func (d *RoundRobinDispatcher) WithHosts(fn func(hosts engine.DispatcherHostProfiles)) {
+	d.RLock()
+	fn(d.hosts)
 	d.RUnlock()
-	return hosts.HostIDs()
 }
 
-func (d *RoundRobinDispatcher) Dispatch(routeID *string, subsystem,
+func (d *RoundRobinDispatcher) Dispatch(ev *utils.CGREvent, argD *utils.ArgDispatcher, subsystem,
 	serviceMethod string, args interface{}, reply interface{}) (err error) {
-	return d.strategy.dispatch(d.dm, routeID, subsystem, d.tnt, d.HostIDs(),
+	routeID, subsystem, hostIDs, err := d.selectHosts(d.strategyDegradations, d.selectionLatency,
+		ev, argD, subsystem, d.HostIDs)
+	if err != nil {
+		return
+	}
+	return d.strategy.dispatch(d.dm, ev, routeID, subsystem, d.tnt, hostIDs,
 		serviceMethod, args, reply)
 }
 
-// BroadcastDispatcher will send the request to multiple hosts simultaneously
-type BroadcastDispatcher struct {
+// RatioDispatcher selects the hosts in a fixed cycle honoring their integer *ratio param,
+// a host with ratio 3 leads three times per cycle, interleaved with the other hosts
+type RatioDispatcher struct {
 	sync.RWMutex
 	dm       *engine.DataManager
 	tnt      string
 	hosts    engine.DispatcherHostProfiles
+	cycles   [][]int // per priority tier, the position of the leading host for each step of the cycle
+	cycleLen int     // number of selections after which all the tiers start again their cycle
+	cycleIdx int     // used for the next connection
 	strategy strategyDispatcher
+	*selectionLatency
+	*strategyDegradations
+	*dispatcherProfile
+	*failoverDepths
 }
 
-func (d *BroadcastDispatcher) SetProfile(pfl *engine.DispatcherProfile) {
+func (d *RatioDispatcher) SetProfile(pfl *engine.DispatcherProfile) {
+	if d.unchangedProfile(pfl) { // keep the strategy state
+		return
+	}
+	sortProfileHosts(pfl)
+	hosts := dispatcherHosts(pfl)
+	cycles, cycleLen, err := ratioCycles(hosts, pfl.StrategyParams)
+	if err != nil {
+		utils.Logger.Warning(fmt.Sprintf("<%s> error: <%s> updating %s strategy for profile %q, keeping previous hosts",
+			utils.DispatcherS, err.Error(), utils.MetaRatio, pfl.TenantID()))
+		return
+	}
 	d.Lock()
-	pfl.Hosts.Sort()
-	d.hosts = pfl.Hosts.Clone()
+	d.hosts = hosts
+	d.cycles = cycles
+	d.cycleLen = cycleLen
+	d.cycleIdx = 0
 	d.Unlock()
+	d.setProfile(pfl)
 	return
 }
 
-func (d *BroadcastDispatcher) HostIDs() (hostIDs []string) {
+func (d *RatioDispatcher) Validate() (err error) {
+	if err = d.validateKillSwitch(); err != nil {
+		return
+	}
 	d.RLock()
-	hostIDs = d.hosts.HostIDs()
-	d.RUnlock()
+	defer d.RUnlock()
+	if err = validateHosts(d.hosts); err != nil {
+		return
+	}
+	tiers := d.hosts.Tiers()
+	if len(d.cycles) != len(tiers) {
+		return fmt.Errorf("%d cycles for %d priority tiers", len(d.cycles), len(tiers))
+	}
+	for i, cycle := range d.cycles {
+		if len(cycle) == 0 || d.cycleLen%len(cycle) != 0 {
+			return fmt.Errorf("cycle of priority tier %d has %d steps out of %d", i, len(cycle), d.cycleLen)
+		}
+		for _, pos := range cycle {
+			if pos < 0 || pos >= len(tiers[i]) {
+				return fmt.Errorf("cycle of priority tier %d leads with host %d out of %d", i, pos, len(tiers[i]))
+			}
+		}
+	}
+	if d.cycleIdx < 0 || d.cycleIdx >= d.cycleLen {
+		return fmt.Errorf("invalid cycle index: %d out of %d", d.cycleIdx, d.cycleLen)
+	}
 	return
 }
 
-func (d *BroadcastDispatcher) Dispatch(routeID *string, subsystem,
-	serviceMethod string, args interface{}, reply interface{}) (lastErr error) { // no cache needed for this strategy because we need to call all connections
-	return d.strategy.dispatch(d.dm, routeID, subsystem, d.tnt, d.HostIDs(),
+func (d *RatioDispatcher) HostIDs() (hostIDs []string) {
+	d.Lock()
+	hosts := d.hosts.Clone()
+	for i, tier := range hosts.Tiers() {
+		pos := d.cycles[i][d.cycleIdx%len(d.cycles[i])]
+		leader := tier[pos] // move the leader in front, the others keep their order for failover
+		copy(tier[1:pos+1], tier[:pos])
+		tier[0] = leader
+	}
+	d.cycleIdx++
+	if d.cycleIdx >= d.cycleLen {
+		d.cycleIdx = 0
+	}
+	d.Unlock()
+	return hosts.HostIDs()
+}
+
+func (d *RatioDispatcher) Status() DispatcherStatus {
+	return d.status(d.strategyDegradations)
+}
+
+func (d *RatioDispatcher) WithHosts(fn func(hosts engine.DispatcherHostProfiles)) {
+	d.RLock()
+	fn(d.hosts)
+	d.RUnlock()
+}
+
+func (d *RatioDispatcher) Dispatch(ev *utils.CGREvent, argD *utils.ArgDispatcher, subsystem,
+	serviceMethod string, args interface{}, reply interface{}) (err error) {
+	routeID, subsystem, hostIDs, err := d.selectHosts(d.strategyDegradations, d.selectionLatency,
+		ev, argD, subsystem, d.HostIDs)
+	if err != nil {
+		return
+	}
+	return d.strategy.dispatch(d.dm, ev, routeID, subsystem, d.tnt, hostIDs,
 		serviceMethod, args, reply)
 }
 
-type singleResultstrategyDispatcher struct{}
+// maxRatioCycle limits the steps of the cycle of a priority tier, the ratios being reduced first
+const maxRatioCycle = 10000
 
-func (_ *singleResultstrategyDispatcher) dispatch(dm *engine.DataManager, routeID *string, subsystem, tnt string,
-	hostIDs []string, serviceMethod string, args interface{}, reply interface{}) (err error) {
-	var dH *engine.DispatcherHost
-	if routeID != nil && *routeID != "" {
-		// overwrite routeID with RouteID:Subsystem
-		*routeID = utils.ConcatenatedKey(*routeID, subsystem)
-		// use previously discovered route
-		if x, ok := engine.Cache.Get(utils.CacheDispatcherRoutes,
-			*routeID); ok && x != nil {
-			dH = x.(*engine.DispatcherHost)
-			if err = dH.Call(serviceMethod, args, reply); !utils.IsNetworkError(err) {
+// maxRatioCycleLen limits the steps after which all the tiers start again their cycle
+const maxRatioCycleLen = math.MaxInt32
+
+// ratioCycles builds for each priority tier of the sorted hosts the smooth weighted cycle
+// of their *ratio params, so each host leads exactly ratio times per cycle.
+// With *percentages the ratios of each tier are percentages which must sum to 100 (*strict)
+// or are scaled to sum to 100 (*normalize)
+func ratioCycles(hosts engine.DispatcherHostProfiles, params map[string]interface{}) (cycles [][]int, cycleLen int, err error) {
+	var pctMode string
+	if pctIface, has := params[utils.MetaPercentages]; has {
+		switch pctMode = utils.IfaceAsString(pctIface); pctMode {
+		case utils.MetaStrict, utils.MetaNormalize:
+		default:
+			return nil, 0, fmt.Errorf("unsupported %s: <%s>", utils.MetaPercentages, pctMode)
+		}
+	}
+	cycleLen = 1
+	for tierIdx, tier := range hosts.Tiers() {
+		ratios := make([]int64, len(tier))
+		var sum int64
+		for i, host := range tier {
+			if ratios[i], err = hostRatio(host); err != nil {
 				return
 			}
+			if ratios[i] < 0 {
+				return nil, 0, fmt.Errorf("negative %s for host %q", utils.MetaRatio, host.ID)
+			}
+			if ratios[i] > math.MaxInt64-sum {
+				return nil, 0, fmt.Errorf("%s of priority tier %d overflow", utils.MetaRatio, tierIdx)
+			}
+			sum += ratios[i]
 		}
-	}
-	for _, hostID := range hostIDs {
-		if dH, err = dm.GetDispatcherHost(tnt, hostID, true, true, utils.NonTransactional); err != nil {
-			err = utils.NewErrDispatcherS(err)
-			return
+		switch {
+		case pctMode == utils.MetaStrict && sum != 100:
+			return nil, 0, fmt.Errorf("%s of priority tier %d sum to %d instead of 100",
+				utils.MetaPercentages, tierIdx, sum)
+		case pctMode == utils.MetaNormalize && sum != 0:
+			ratios, sum = normalizedPercentages(ratios, sum), 100
 		}
-		if err = dH.Call(serviceMethod, args, reply); utils.IsNetworkError(err) {
+		if sum == 0 { // no host leads, keep the sorted order
+			cycles = append(cycles, []int{0})
 			continue
 		}
-		if routeID != nil && *routeID != "" { // cache the discovered route
-			if err = engine.Cache.Set(utils.CacheDispatcherRoutes, *routeID, dH,
-				nil, true, utils.EmptyString); err != nil {
-				return
+		ratios, sum = reducedRatios(ratios, sum)
+		if sum > maxRatioCycle {
+			return nil, 0, fmt.Errorf("%s of priority tier %d need a cycle of %d steps, more than %d",
+				utils.MetaRatio, tierIdx, sum, maxRatioCycle)
+		}
+		cycle := make([]int, sum)
+		current := make([]int64, len(tier))
+		for step := range cycle {
+			var pos int
+			for i := range current {
+				current[i] += ratios[i]
+				if current[i] > current[pos] {
+					pos = i
+				}
 			}
+			current[pos] -= sum
+			cycle[step] = pos
+		}
+		cycles = append(cycles, cycle)
+		if cycleLen = lcm(cycleLen, len(cycle)); cycleLen > maxRatioCycleLen {
+			return nil, 0, fmt.Errorf("%s cycles of the priority tiers do not combine in less than %d steps",
+				utils.MetaRatio, maxRatioCycleLen)
 		}
-		break
 	}
 	return
 }
 
-type brodcastStrategyDispatcher struct{}
-
-func (_ *brodcastStrategyDispatcher) dispatch(dm *engine.DataManager, routeID *string, subsystem, tnt string, hostIDs []string,
-	serviceMethod string, args interface{}, reply interface{}) (err error) {
-	var hasErrors bool
-	for _, hostID := range hostIDs {
-		var dH *engine.DispatcherHost
-		if dH, err = dm.GetDispatcherHost(tnt, hostID, true, true, utils.NonTransactional); err != nil {
-			err = utils.NewErrDispatcherS(err)
-			return
-		}
-		if err = dH.Call(serviceMethod, args, reply); utils.IsNetworkError(err) {
-			utils.Logger.Err(fmt.Sprintf("<%s> network error: <%s> at %s strategy for hostID %q",
-				utils.DispatcherS, err.Error(), utils.MetaBroadcast, hostID))
-			hasErrors = true
-		} else if err != nil {
-			utils.Logger.Err(fmt.Sprintf("<%s> error: <%s> at %s strategy for hostID %q",
-				utils.DispatcherS, err.Error(), utils.MetaBroadcast, hostID))
-			hasErrors = true
+// reducedRatios divides the ratios by their greatest common divisor so the cycle is as short as possible
+func reducedRatios(ratios []int64, sum int64) ([]int64, int64) {
+	div := sum
+	for _, ratio := range ratios {
+		for ratio != 0 {
+			div, ratio = ratio, div%ratio
 		}
 	}
-	if hasErrors { // rewrite err if not all call were succesfull
-		return utils.ErrPartiallyExecuted
+	if div <= 1 {
+		return ratios, sum
 	}
-	return
+	for i := range ratios {
+		ratios[i] /= div
+	}
+	return ratios, sum / div
 }
 
-func newLoadStrategyDispatcher(hosts engine.DispatcherHostProfiles, tntID string) (ls *loadStrategyDispatcher, err error) {
-	ls = &loadStrategyDispatcher{
-		tntID: tntID,
-		hosts: hosts,
+// normalizedPercentages scales the ratios to percentages summing to 100,
+// giving the points lost by rounding down to the ratios with the largest remainders.
+// The ratios are scaled as big integers since any ratio above math.MaxInt64/100 would overflow
+func normalizedPercentages(ratios []int64, sum int64) (pcts []int64) {
+	pcts = make([]int64, len(ratios))
+	rems := make([]int64, len(ratios))
+	order := make([]int, len(ratios))
+	left := int64(100)
+	bigSum, bigPct, quo, rem := big.NewInt(sum), big.NewInt(100), new(big.Int), new(big.Int)
+	for i, ratio := range ratios {
+		quo.QuoRem(new(big.Int).Mul(big.NewInt(ratio), bigPct), bigSum, rem)
+		pcts[i], rems[i] = quo.Int64(), rem.Int64() // quo is at most 100 and rem less than sum
+		left -= pcts[i]
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return rems[order[i]] > rems[order[j]]
+	})
+	for _, i := range order[:left] {
+		pcts[i]++
 	}
-
 	return
 }
 
-type loadStrategyDispatcher struct {
-	tntID string
-	hosts engine.DispatcherHostProfiles
+// hostRatio returns the *ratio param of the host, 1 if not configured
+func hostRatio(host *engine.DispatcherHostProfile) (ratio int64, err error) {
+	strRatio, has := host.Params[utils.MetaRatio]
+	if !has {
+		return 1, nil
+	}
+	return strconv.ParseInt(utils.IfaceAsString(strRatio), 10, 64)
 }
 
-func newLoadMetrics(hosts engine.DispatcherHostProfiles) (*LoadMetrics, error) {
-	lM := &LoadMetrics{
-		HostsLoad:  make(map[string]int64),
-		HostsRatio: make(map[string]int64),
-		SumRatio:   0,
+// BroadcastDispatcher will send the request to multiple hosts simultaneously
+// with *broadcast and *broadcast_sync the Dispatch waits for all the calls and aggregates their errors
+// with *broadcast_async the calls are made one after the other in background and the Dispatch returns without waiting for them
+type BroadcastDispatcher struct {
+	sync.RWMutex
+	dm       *engine.DataManager
+	tnt      string
+	hosts    engine.DispatcherHostProfiles
+	strategy strategyDispatcher
+	*selectionLatency
+	*strategyDegradations
+	*dispatcherProfile
+	*failoverDepths
+}
+
+func (d *BroadcastDispatcher) SetProfile(pfl *engine.DispatcherProfile) {
+	if d.unchangedProfile(pfl) { // keep the strategy state
+		return
 	}
-	for _, host := range hosts {
-		if strRatio, has := host.Params[utils.MetaRatio]; !has {
-			lM.HostsRatio[host.ID] = 1
-			lM.SumRatio += 1
-		} else if ratio, err := strconv.ParseInt(utils.IfaceAsString(strRatio), 10, 64); err != nil {
-			return nil, err
-		} else {
-			lM.HostsRatio[host.ID] = ratio
-			lM.SumRatio += ratio
-		}
+	bs, err := newBrodcastStrategyDispatcher(pfl.Strategy, pfl.StrategyParams)
+	if err != nil {
+		utils.Logger.Warning(fmt.Sprintf("<%s> error: <%s> updating %s strategy for profile %q, keeping previous strategy params",
+			utils.DispatcherS, err.Error(), pfl.Strategy, pfl.TenantID()))
 	}
-	return lM, nil
+	d.Lock()
+	sortProfileHosts(pfl)
+	d.hosts = dispatcherHosts(pfl)
+	if err == nil {
+		bs.degradations = d.strategyDegradations
+		d.strategy = bs
+	}
+	d.Unlock()
+	d.setProfile(pfl)
+	return
 }
 
-type LoadMetrics struct {
-	mutex      sync.RWMutex
-	HostsLoad  map[string]int64
-	HostsRatio map[string]int64
-	SumRatio   int64
+// BroadcastMode returns *broadcast_sync if the caller should wait for all the replies
+// or *broadcast_async if the calls are fired without waiting for them
+func (d *BroadcastDispatcher) BroadcastMode() (mode string) {
+	d.RLock()
+	mode = d.strategy.(*brodcastStrategyDispatcher).mode()
+	d.RUnlock()
+	return
 }
 
-func (ld *loadStrategyDispatcher) dispatch(dm *engine.DataManager, routeID *string, subsystem, tnt string, hostIDs []string,
-	serviceMethod string, args interface{}, reply interface{}) (err error) {
-	var dH *engine.DispatcherHost
-	var lM *LoadMetrics
-	if x, ok := engine.Cache.Get(utils.CacheDispatcherLoads, ld.tntID); ok && x != nil {
-		var canCast bool
-		if lM, canCast = x.(*LoadMetrics); !canCast {
-			return fmt.Errorf("cannot cast %+v to *LoadMetrics", x)
-		}
-	} else if lM, err = newLoadMetrics(ld.hosts); err != nil {
+func (d *BroadcastDispatcher) Validate() (err error) {
+	if err = d.validateKillSwitch(); err != nil {
 		return
 	}
-
-	if routeID != nil && *routeID != "" {
-		// overwrite routeID with RouteID:Subsystem
-		*routeID = utils.ConcatenatedKey(*routeID, subsystem)
-		// use previously discovered route
-		if x, ok := engine.Cache.Get(utils.CacheDispatcherRoutes,
-			*routeID); ok && x != nil {
-			dH = x.(*engine.DispatcherHost)
-			lM.incrementLoad(dH.ID, ld.tntID)
-			err = dH.Call(serviceMethod, args, reply)
-			lM.decrementLoad(dH.ID, ld.tntID) // call ended
-			if !utils.IsNetworkError(err) {
-				return
-			}
-		}
+	d.RLock()
+	defer d.RUnlock()
+	if err = validateHosts(d.hosts); err != nil {
+		return
 	}
-	for _, hostID := range lM.getHosts(hostIDs) {
-		if dH, err = dm.GetDispatcherHost(tnt, hostID, true, true, utils.NonTransactional); err != nil {
-			err = utils.NewErrDispatcherS(err)
-			return
-		}
+	if bs, canCast := d.strategy.(*brodcastStrategyDispatcher); canCast && bs.quorum < 0 {
+		return fmt.Errorf("invalid %s: <%d>", utils.MetaQuorum, bs.quorum)
+	}
+	return
+}
+
+func (d *BroadcastDispatcher) HostIDs() (hostIDs []string) {
+	d.RLock()
+	hostIDs = d.hosts.HostIDs()
+	d.RUnlock()
+	return
+}
+
+func (d *BroadcastDispatcher) Status() DispatcherStatus {
+	return d.status(d.strategyDegradations)
+}
+
+func (d *BroadcastDispatcher) WithHosts(fn func(hosts engine.DispatcherHostProfiles)) {
+	d.RLock()
+	fn(d.hosts)
+	d.RUnlock()
+}
+
+func (d *BroadcastDispatcher) Dispatch(ev *utils.CGREvent, argD *utils.ArgDispatcher, subsystem,
+	serviceMethod string, args interface{}, reply interface{}) (lastErr error) { // no cache needed for this strategy because we need to call all connections
+	d.RLock()
+	strategy := d.strategy
+	d.RUnlock()
+	if ksHostIDs, active, ksErr := d.killSwitch(); active {
+		d.degraded(DegradedKillSwitch)
+		if ksErr != nil {
+			return ksErr
+		}
+		return strategy.dispatch(d.dm, ev, nil, subsystem, d.tnt, ksHostIDs,
+			serviceMethod, args, reply)
+	}
+	start := d.startSelection()
+	hostIDs := d.HostIDs()
+	d.recordSelection(start)
+	routeID, _ := dispatchArgs(argD)
+	return strategy.dispatch(d.dm, ev, routeID, subsystem, d.tnt, hostIDs,
+		serviceMethod, args, reply)
+}
+
+// newTieBreaker returns the tieBreaker configured with *tie_break in StrategyParams
+// nil is returned if no tie break is configured
+func newTieBreaker(params map[string]interface{}) (tb *tieBreaker, err error) {
+	tbIface, has := params[utils.MetaTieBreak]
+	if !has {
+		return
+	}
+	switch tbPolicy := utils.IfaceAsString(tbIface); tbPolicy {
+	case utils.MetaID, utils.MetaRandom, utils.MetaRoundRobin:
+		return &tieBreaker{tbPolicy: tbPolicy}, nil
+	default:
+		return nil, fmt.Errorf("unsupported %s: <%s>", utils.MetaTieBreak, tbPolicy)
+	}
+}
+
+// tieBreaker orders the hosts the strategy considers equal:
+// *id sorts them by ID, *random shuffles them and *round_robin rotates them on each selection
+type tieBreaker struct {
+	sync.Mutex
+	tbPolicy string
+	rrIdx    int // rotation used by *round_robin
+}
+
+// policy returns the tie break policy, empty for no tie break
+func (tb *tieBreaker) policy() string {
+	if tb == nil {
+		return utils.EmptyString
+	}
+	return tb.tbPolicy
+}
+
+// next returns the policy and the rotation to use for the next selection, advancing the *round_robin one
+func (tb *tieBreaker) next() (tbPolicy string, rrIdx int) {
+	if tb == nil {
+		return
+	}
+	if tb.tbPolicy == utils.MetaRoundRobin {
+		tb.Lock()
+		rrIdx = tb.rrIdx
+		tb.rrIdx++
+		if tb.rrIdx < 0 { // overflow
+			tb.rrIdx = 0
+		}
+		tb.Unlock()
+	}
+	return tb.tbPolicy, rrIdx
+}
+
+// breakTies applies the next tie break selection over the hostIDs
+func (tb *tieBreaker) breakTies(hostIDs []string, tied func(i, j int) bool) {
+	tbPolicy, rrIdx := tb.next()
+	breakTies(hostIDs, tied, tbPolicy, rrIdx)
+}
+
+// breakTies reorders in place the groups of consecutive hostIDs for which tied returns true
+// based on the tie break policy, rrIdx being the rotation used by *round_robin
+func breakTies(hostIDs []string, tied func(i, j int) bool, tbPolicy string, rrIdx int) {
+	var start int
+	for i := 1; i <= len(hostIDs); i++ {
+		if i != len(hostIDs) && tied(start, i) {
+			continue
+		}
+		if tiedIDs := hostIDs[start:i]; len(tiedIDs) > 1 {
+			switch tbPolicy {
+			case utils.MetaID:
+				sort.Strings(tiedIDs)
+			case utils.MetaRandom:
+				rand.Shuffle(len(tiedIDs), func(i, j int) {
+					tiedIDs[i], tiedIDs[j] = tiedIDs[j], tiedIDs[i]
+				})
+			case utils.MetaRoundRobin:
+				rotateHostIDs(tiedIDs, rrIdx%len(tiedIDs))
+			}
+		}
+		start = i
+	}
+}
+
+// weightHostIDs returns the IDs of the sorted hosts with the hosts of equal priority and weight
+// ordered by the tie break policy, without changing the hosts
+func weightHostIDs(hosts engine.DispatcherHostProfiles, tbPolicy string, rrIdx int) (hostIDs []string) {
+	hostIDs = hosts.HostIDs()
+	if tbPolicy != utils.EmptyString {
+		breakTies(hostIDs, func(i, j int) bool {
+			iPrio, _ := hosts[i].Priority()
+			jPrio, _ := hosts[j].Priority()
+			return iPrio == jPrio && hosts[i].Weight == hosts[j].Weight
+		}, tbPolicy, rrIdx)
+	}
+	return
+}
+
+// rotateHostIDs rotates in place the hostIDs so the one at idx becomes first
+func rotateHostIDs(hostIDs []string, idx int) {
+	rotated := append(append(make([]string, 0, len(hostIDs)), hostIDs[idx:]...), hostIDs[:idx]...)
+	copy(hostIDs, rotated)
+}
+
+func newDispatcherProfile(pfl *engine.DispatcherProfile) *dispatcherProfile {
+	return &dispatcherProfile{pfl: pfl.Clone(), hash: profileHash(pfl)}
+}
+
+// dispatcherProfile keeps a private copy of the profile the dispatcher was last configured with
+type dispatcherProfile struct {
+	pflMux    sync.RWMutex
+	pfl       *engine.DispatcherProfile
+	hash      string
+	auditHook *auditHook // nil without DispatcherService
+}
+
+// setProfile stores a copy of the profile
+func (dp *dispatcherProfile) setProfile(pfl *engine.DispatcherProfile) {
+	hash := profileHash(pfl)
+	pfl = pfl.Clone()
+	dp.pflMux.Lock()
+	dp.pfl = pfl
+	dp.hash = hash
+	dp.pflMux.Unlock()
+}
+
+// unchangedProfile returns true if the profile has the same hash as the current one
+// in which case only the profile copy is refreshed so the dispatcher keeps its strategy state
+func (dp *dispatcherProfile) unchangedProfile(pfl *engine.DispatcherProfile) bool {
+	if profileHash(pfl) != dp.ProfileHash() {
+		return false
+	}
+	sortProfileHosts(pfl)
+	dp.setProfile(pfl)
+	return true
+}
+
+// ProfileHash returns the hash of the profile computed on the last setProfile
+func (dp *dispatcherProfile) ProfileHash() string {
+	dp.pflMux.RLock()
+	defer dp.pflMux.RUnlock()
+	return dp.hash
+}
+
+// dispatchArgs returns the RouteID and the strategy override carried by the ArgDispatcher
+func dispatchArgs(argD *utils.ArgDispatcher) (routeID, strategy *string) {
+	if argD == nil {
+		return
+	}
+	return argD.RouteID, argD.Strategy
+}
+
+// hostIDsOverride orders the hosts with the strategy requested in the ArgDispatcher of the call,
+// for this call only, without changing the dispatcher. Only *weight and *random can be requested
+// and only over *weight, *random, *round_robin or *ratio profiles, otherwise the profile strategy is used
+// and the override is counted as degraded
+func (dp *dispatcherProfile) hostIDsOverride(ovrStrategy *string, sd *strategyDegradations) (hostIDs []string, overridden bool) {
+	if ovrStrategy == nil || *ovrStrategy == utils.EmptyString {
+		return
+	}
+	strategy := *ovrStrategy
+	dp.pflMux.RLock()
+	pflStrategy := dp.pfl.Strategy
+	hosts, _ := uniqueHosts(dp.pfl.Hosts)
+	dp.pflMux.RUnlock()
+	if strategy == pflStrategy {
+		return
+	}
+	switch pflStrategy {
+	case utils.MetaWeight, utils.MetaRandom, utils.MetaRoundRobin, utils.MetaRatio:
+	default:
+		sd.degraded(DegradedUnsupportedOverride)
+		return
+	}
+	switch strategy {
+	case utils.MetaWeight:
+		hosts.Sort()
+	case utils.MetaRandom:
+		for _, tier := range hosts.Tiers() {
+			tier.Shuffle()
+		}
+	default:
+		sd.degraded(DegradedUnsupportedOverride)
+		return
+	}
+	return hosts.HostIDs(), true
+}
+
+// selectHosts runs the steps shared by the single result strategies before dispatching:
+// the *kill_switch, bypassing the strategy and the route, then the affinity route and
+// the strategy override of the ArgDispatcher. hostIDs is only called if the order is not
+// overridden so the override does not move the state of the strategy
+func (dp *dispatcherProfile) selectHosts(sd *strategyDegradations, sl *selectionLatency,
+	ev *utils.CGREvent, argD *utils.ArgDispatcher, subsystem string, hostIDs func() []string) (routeID *string,
+	rSubsystem string, selected []string, err error) {
+	var active bool
+	if selected, active, err = dp.killSwitch(); active {
+		sd.degraded(DegradedKillSwitch)
+		return nil, subsystem, selected, err
+	}
+	routeID, strategy := dispatchArgs(argD)
+	routeID, rSubsystem = dp.affinityRoute(ev, routeID, subsystem)
+	start := sl.startSelection()
+	var overridden bool
+	if selected, overridden = dp.hostIDsOverride(strategy, sd); !overridden {
+		selected = hostIDs()
+	}
+	sl.recordSelection(start)
+	return
+}
+
+// killSwitchMode returns the *kill_switch strategy param, empty when the kill switch is off
+func killSwitchMode(params map[string]interface{}) string {
+	ksIface, has := params[utils.MetaKillSwitch]
+	if !has {
+		return utils.EmptyString
+	}
+	return utils.IfaceAsString(ksIface)
+}
+
+// killSwitch returns the hosts forced by the *kill_switch strategy param, bypassing the strategy:
+// *reject refuses all the calls and a host ID sends all of them to that host, without failover
+func (dp *dispatcherProfile) killSwitch() (hostIDs []string, active bool, err error) {
+	dp.pflMux.RLock()
+	ks := killSwitchMode(dp.pfl.StrategyParams)
+	dp.pflMux.RUnlock()
+	switch ks {
+	case utils.EmptyString:
+		return
+	case utils.MetaReject:
+		return nil, true, utils.NewErrDispatcherS(utils.ErrDispatcherDisabled)
+	default:
+		return []string{ks}, true, nil
+	}
+}
+
+// validateKillSwitch checks that the host forced by *kill_switch is one of the profile hosts
+func (dp *dispatcherProfile) validateKillSwitch() error {
+	dp.pflMux.RLock()
+	defer dp.pflMux.RUnlock()
+	switch ks := killSwitchMode(dp.pfl.StrategyParams); ks {
+	case utils.EmptyString, utils.MetaReject:
+		return nil
+	default:
+		if !utils.IsSliceMember(dp.pfl.Hosts.HostIDs(), ks) {
+			return fmt.Errorf("%s host %q not part of the profile", utils.MetaKillSwitch, ks)
+		}
+	}
+	return nil
+}
+
+// DispatcherStatus is the state of a dispatcher as rendered by monitoring in one call
+type DispatcherStatus struct {
+	Tenant       string
+	ID           string
+	Strategy     string
+	Hosts        int              // distinct hosts in the pool
+	KillSwitch   string           // empty when off, *reject or the ID of the host receiving all the calls
+	Degraded     bool             // the kill switch is active or the strategy took a degraded path within degradedStatusWindow
+	Degradations map[string]int64 // degradations per reason, see Degradations
+}
+
+// status returns the profile part of the status, taken under a single read lock,
+// completed with the degradations counters
+func (dp *dispatcherProfile) status(sd *strategyDegradations) (st DispatcherStatus) {
+	dp.pflMux.RLock()
+	st = DispatcherStatus{
+		Tenant:     dp.pfl.Tenant,
+		ID:         dp.pfl.ID,
+		Strategy:   dp.pfl.Strategy,
+		Hosts:      utils.NewStringSet(dp.pfl.Hosts.HostIDs()).Size(),
+		KillSwitch: killSwitchMode(dp.pfl.StrategyParams),
+	}
+	dp.pflMux.RUnlock()
+	st.Degradations = sd.Degradations()
+	st.Degraded = st.KillSwitch != utils.EmptyString ||
+		sd.degradedSince(time.Now().Add(-degradedStatusWindow))
+	return
+}
+
+// affinityRoute returns the route to use for the event when no RouteID is given and *affinity_field is configured:
+// the tenant with the value of that event field, shared by all the subsystems so they prefer the same host for it.
+// A cached route to a host outside the profile of the subsystem is ignored by the dispatch
+func (dp *dispatcherProfile) affinityRoute(ev *utils.CGREvent, routeID *string,
+	subsystem string) (affRouteID *string, affSubsystem string) {
+	if ev == nil || (routeID != nil && *routeID != utils.EmptyString) {
+		return routeID, subsystem
+	}
+	dp.pflMux.RLock()
+	tnt := dp.pfl.Tenant
+	fldIface, has := dp.pfl.StrategyParams[utils.MetaAffinityField]
+	dp.pflMux.RUnlock()
+	if !has {
+		return routeID, subsystem
+	}
+	fldName := utils.IfaceAsString(fldIface)
+	if key, err := ev.FieldAsString(fldName); err == nil && key != utils.EmptyString {
+		return utils.StringPointer(utils.ConcatenatedKey(tnt, fldName, key)), utils.META_ANY
+	}
+	return routeID, subsystem
+}
+
+// profileHash returns a hash over the fields of the profile driving the dispatching:
+// the strategy with its params and the hosts, sorted by ID so their order does not count
+// unless *preserve_order is enabled
+func profileHash(pfl *engine.DispatcherProfile) string {
+	hosts := pfl.Hosts.Clone()
+	if preserve, _ := preserveOrder(pfl.StrategyParams); !preserve {
+		sort.Slice(hosts, func(i, j int) bool {
+			return hosts[i].ID < hosts[j].ID
+		})
+	} else { // same grouping as the dispatchers so the hash does not depend on the tiers being listed in order
+		sortHostsByPriority(hosts)
+	}
+	return utils.Sha1(utils.ToJSON(struct {
+		Strategy       string
+		StrategyParams map[string]interface{}
+		Hosts          engine.DispatcherHostProfiles
+	}{pfl.Strategy, pfl.StrategyParams, hosts}))
+}
+
+// Profile returns a copy of the profile so it can be changed without affecting the dispatcher
+func (dp *dispatcherProfile) Profile() *engine.DispatcherProfile {
+	dp.pflMux.RLock()
+	defer dp.pflMux.RUnlock()
+	return dp.pfl.Clone()
+}
+
+func newFailoverDepths() *failoverDepths {
+	return &failoverDepths{depths: make(map[int]int)}
+}
+
+// failoverDepths counts the answered calls per position of the answering host in the failover order,
+// a growing share of non zero depths shows the first hosts degrading before the calls start failing
+type failoverDepths struct {
+	mutex  sync.Mutex
+	depths map[int]int
+}
+
+// record counts one call answered by the host at depth in the failover order
+func (fd *failoverDepths) record(depth int) {
+	if fd == nil {
+		return
+	}
+	fd.mutex.Lock()
+	fd.depths[depth]++
+	fd.mutex.Unlock()
+}
+
+// FailoverDepthDistribution returns a copy of the counters per failover depth
+func (fd *failoverDepths) FailoverDepthDistribution() (depths map[int]int) {
+	depths = make(map[int]int)
+	if fd == nil {
+		return
+	}
+	fd.mutex.Lock()
+	for depth, count := range fd.depths {
+		depths[depth] = count
+	}
+	fd.mutex.Unlock()
+	return
+}
+
+// AuditRecord describes a routing decision of the single result strategies, see DispatcherService.SetAuditHook
+type AuditRecord struct {
+	Time      time.Time
+	Tenant    string
+	ProfileID string
+	Strategy  string
+	EventID   string   // ID of the event the profile was matched for, empty without event
+	RouteID   string   // RouteID:Subsystem of the call, empty without RouteID or *affinity_field
+	HostID    string   // the host that answered the call, empty if none did
+	Failover  bool     // the call was tried on other hosts first, or on HostID as cached route
+	Skipped   []string // the other hosts failing with network errors, once each, in the order they were tried
+	Error     string   // the error of the call, the one of the last attempt if no host answered
+}
+
+// auditHook holds the optional function receiving the AuditRecords, shared by the dispatchers
+// of a DispatcherService so it can be changed while they dispatch
+type auditHook struct {
+	hook atomic.Value // func(rec AuditRecord)
+}
+
+func (ah *auditHook) set(hook func(rec AuditRecord)) {
+	ah.hook.Store(hook)
+}
+
+// get returns the hook, nil if none is set
+func (ah *auditHook) get() (hook func(rec AuditRecord)) {
+	if ah == nil {
+		return
+	}
+	hook, _ = ah.hook.Load().(func(rec AuditRecord))
+	return
+}
+
+// audit passes the routing decision to the audit hook, if set,
+// failed being the attempts that ended with network errors, hostID included if it answered as second try
+func (dp *dispatcherProfile) audit(ev *utils.CGREvent, routeID *string, hostID string, failed []string, err error) {
+	if dp == nil {
+		return
+	}
+	hook := dp.auditHook.get()
+	if hook == nil {
+		return
+	}
+	rec := AuditRecord{
+		Time:     time.Now(),
+		HostID:   hostID,
+		Failover: len(failed) != 0,
+	}
+	tried := utils.StringSet{hostID: {}}
+	for _, id := range failed { // the cached route can be tried again by the failover
+		if !tried.Has(id) {
+			tried.Add(id)
+			rec.Skipped = append(rec.Skipped, id)
+		}
+	}
+	if ev != nil {
+		rec.EventID = ev.ID
+	}
+	if routeID != nil {
+		rec.RouteID = *routeID
+	}
+	if err != nil {
+		rec.Error = err.Error()
+	}
+	dp.pflMux.RLock()
+	rec.Tenant, rec.ProfileID, rec.Strategy = dp.pfl.Tenant, dp.pfl.ID, dp.pfl.Strategy
+	dp.pflMux.RUnlock()
+	hook(rec)
+}
+
+// Degradation reasons, the strategies fall back to a simpler behavior for these
+const (
+	DegradedAllHostsLoaded      = "AllHostsLoaded"      // *load with all hosts over their ratio orders by load only
+	DegradedMissingEventID      = "MissingEventID"      // *event_id_seed without event ID falls back to pure random
+	DegradedQuorumOverHosts     = "QuorumOverHosts"     // *quorum higher than the number of hosts needs all of them
+	DegradedKillSwitch          = "KillSwitch"          // *kill_switch bypasses the strategy, rejecting or forcing one host
+	DegradedUnsupportedOverride = "UnsupportedOverride" // the strategy override of the call is ignored for the profile strategy
+)
+
+// degradedLogInterval limits the warnings logged for the same degradation reason
+const degradedLogInterval = time.Minute
+
+// degradedStatusWindow is how long a degradation keeps the status of the dispatcher as degraded
+const degradedStatusWindow = time.Minute
+
+func newStrategyDegradations(tntID, strategy string) *strategyDegradations {
+	return &strategyDegradations{
+		tntID:    tntID,
+		strategy: strategy,
+		counts:   make(map[string]int64),
+		last:     make(map[string]time.Time),
+		logged:   make(map[string]time.Time),
+	}
+}
+
+// strategyDegradations counts the times a strategy took a degraded path
+// so misconfigurations hidden by the fallbacks become visible
+type strategyDegradations struct {
+	mutex    sync.Mutex
+	tntID    string
+	strategy string
+	counts   map[string]int64
+	last     map[string]time.Time // last degradation per reason
+	logged   map[string]time.Time // last warning per reason
+}
+
+// degraded records one degradation, logging a warning at most once per degradedLogInterval for the reason
+func (sd *strategyDegradations) degraded(reason string) {
+	if sd == nil {
+		return
+	}
+	sd.mutex.Lock()
+	sd.counts[reason]++
+	count := sd.counts[reason]
+	now := time.Now()
+	sd.last[reason] = now
+	logIt := now.Sub(sd.logged[reason]) >= degradedLogInterval
+	if logIt {
+		sd.logged[reason] = now
+	}
+	sd.mutex.Unlock()
+	if logIt {
+		utils.Logger.Warning(fmt.Sprintf("<%s> profile %q with %s strategy degraded with reason: %s, total occurrences: %d",
+			utils.DispatcherS, sd.tntID, sd.strategy, reason, count))
+	}
+}
+
+// Degradations returns a copy of the degradation counters
+func (sd *strategyDegradations) Degradations() (dgs map[string]int64) {
+	dgs = make(map[string]int64)
+	if sd == nil {
+		return
+	}
+	sd.mutex.Lock()
+	for reason, count := range sd.counts {
+		dgs[reason] = count
+	}
+	sd.mutex.Unlock()
+	return
+}
+
+// degradedSince returns true if the strategy took a degraded path after since
+func (sd *strategyDegradations) degradedSince(since time.Time) (degraded bool) {
+	if sd == nil {
+		return
+	}
+	sd.mutex.Lock()
+	for _, last := range sd.last {
+		if last.After(since) {
+			degraded = true
+			break
+		}
+	}
+	sd.mutex.Unlock()
+	return
+}
+
+// selectionLatencySamples is the number of latest selections considered for the percentiles
+const selectionLatencySamples = 1024
+
+// newSelectionLatency returns a selectionLatency if enabled with *selection_latency in StrategyParams
+// when disabled nil is returned so the dispatchers do not pay for the recording
+func newSelectionLatency(params map[string]interface{}) (sl *selectionLatency, err error) {
+	enabled, has := params[utils.MetaSelectionLatency]
+	if !has {
+		return
+	}
+	var record bool
+	if record, err = utils.IfaceAsBool(enabled); err != nil || !record {
+		return
+	}
+	return &selectionLatency{samples: make([]time.Duration, 0, selectionLatencySamples)}, nil
+}
+
+// selectionLatency records the time spent by a dispatcher to order the hosts
+// it does not include the time spent calling the hosts
+type selectionLatency struct {
+	mutex   sync.Mutex
+	samples []time.Duration // ring buffer with the latest selections
+	idx     int             // position of the next sample
+}
+
+// startSelection returns the time the selection started at, zero if not recording
+func (sl *selectionLatency) startSelection() (start time.Time) {
+	if sl == nil {
+		return
+	}
+	return time.Now()
+}
+
+// recordSelection stores the time spent since start
+func (sl *selectionLatency) recordSelection(start time.Time) {
+	if sl == nil {
+		return
+	}
+	dur := time.Since(start)
+	sl.mutex.Lock()
+	if len(sl.samples) < selectionLatencySamples {
+		sl.samples = append(sl.samples, dur)
+	} else {
+		sl.samples[sl.idx] = dur
+	}
+	sl.idx = (sl.idx + 1) % selectionLatencySamples
+	sl.mutex.Unlock()
+}
+
+// SelectionLatency returns the percentiles out of the latest recorded selections
+func (sl *selectionLatency) SelectionLatency() (p50, p95, p99 time.Duration) {
+	if sl == nil {
+		return
+	}
+	sl.mutex.Lock()
+	samples := make([]time.Duration, len(sl.samples))
+	copy(samples, sl.samples)
+	sl.mutex.Unlock()
+	if len(samples) == 0 {
+		return
+	}
+	sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+	return durationPercentile(samples, 50),
+		durationPercentile(samples, 95),
+		durationPercentile(samples, 99)
+}
+
+// durationPercentile returns the nearest-rank percentile out of the sorted durations
+func durationPercentile(sorted []time.Duration, pct int) time.Duration {
+	idx := (len(sorted)*pct+99)/100 - 1
+	if idx < 0 {
+		idx = 0
+	}
+	return sorted[idx]
+}
+
+// lcm returns the least common multiple of a and b
+func lcm(a, b int) int {
+	x, y := a, b
+	for y != 0 {
+		x, y = y, x%y
+	}
+	return a / x * b
+}
+
+// eventIDSeed reads the *event_id_seed option out of the profile StrategyParams
+func eventIDSeed(params map[string]interface{}) (idSeed bool, err error) {
+	if seed, has := params[utils.MetaEventIDSeed]; has {
+		return utils.IfaceAsBool(seed)
+	}
+	return
+}
+
+// preserveOrder returns if the *preserve_order strategy param is enabled
+// so the hosts are used in the configured order inside their priority tier instead of being sorted by weight
+func preserveOrder(params map[string]interface{}) (preserve bool, err error) {
+	if po, has := params[utils.MetaPreserveOrder]; has {
+		return utils.IfaceAsBool(po)
+	}
+	return
+}
+
+// sortProfileHosts sorts the hosts of the profile unless *preserve_order is enabled,
+// an invalid *preserve_order is logged and the hosts sorted
+func sortProfileHosts(pfl *engine.DispatcherProfile) {
+	preserve, err := preserveOrder(pfl.StrategyParams)
+	if err != nil {
+		utils.Logger.Warning(fmt.Sprintf("<%s> error: <%s> updating %s strategy for profile %q, sorting the hosts",
+			utils.DispatcherS, err.Error(), pfl.Strategy, pfl.TenantID()))
+	}
+	if !preserve {
+		pfl.Hosts.Sort()
+	} else {
+		sortHostsByPriority(pfl.Hosts)
+	}
+}
+
+// sortHostsByPriority groups the hosts in priority tiers, lowest first, keeping their order inside the tier
+// so the preserved order still gives contiguous tiers
+func sortHostsByPriority(hosts engine.DispatcherHostProfiles) {
+	sort.SliceStable(hosts, func(i, j int) bool {
+		iPrio, _ := hosts[i].Priority()
+		jPrio, _ := hosts[j].Priority()
+		return iPrio < jPrio
+	})
+}
+
+// antiAffinity returns if the *anti_affinity strategy param is enabled
+func antiAffinity(params map[string]interface{}) (antiAff bool, err error) {
+	if aa, has := params[utils.MetaAntiAffinity]; has {
+		return utils.IfaceAsBool(aa)
+	}
+	return
+}
+
+// seedFromID computes a random seed out of the ID
+func seedFromID(id string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(id))
+	return int64(h.Sum64())
+}
+
+// HostIDsMatching returns the ordered list of host IDs of the dispatcher with Params matching all the required tags
+func HostIDsMatching(d Dispatcher, tags map[string]string) (hostIDs []string) {
+	hostIDs = d.HostIDs()
+	d.WithHosts(func(hosts engine.DispatcherHostProfiles) {
+		hostIDs = matchingHostIDs(hostIDs, hosts, tags)
+	})
+	return
+}
+
+// HostIDsExcept returns the ordered list of host IDs of the dispatcher without the excluded ones
+func HostIDsExcept(d Dispatcher, exclude utils.StringSet) (hostIDs []string) {
+	return exceptHostIDs(d.HostIDs(), exclude)
+}
+
+// HostIDsHint returns the ordered list of host IDs of the dispatcher starting with the preferred one
+// used is false if the preferred host is not part of the pool, in which case the order is not changed
+func HostIDsHint(d Dispatcher, preferred string) (hostIDs []string, used bool) {
+	return preferHostIDs(d.HostIDs(), []string{preferred})
+}
+
+// HostIDsPreferring returns the ordered list of host IDs of the dispatcher starting with the preferred ones
+// part of the pool, in the order they are given, used being false if none of them is
+func HostIDsPreferring(d Dispatcher, preferred []string) (hostIDs []string, used bool) {
+	return preferHostIDs(d.HostIDs(), preferred)
+}
+
+// SelectN returns up to n distinct host IDs in the order given by the strategy of the dispatcher
+func SelectN(d Dispatcher, n int) (hostIDs []string) {
+	return firstHostIDs(d.HostIDs(), n)
+}
+
+// WeightedOrder returns all the host IDs of the dispatcher once, the heavier hosts being more likely to come first
+func WeightedOrder(d Dispatcher) (hostIDs []string) {
+	d.WithHosts(func(hosts engine.DispatcherHostProfiles) {
+		hostIDs = weightedOrder(hosts)
+	})
+	return
+}
+
+// matchingHostIDs keeps the order of hostIDs returning only the hosts tagged with all the required tags
+func matchingHostIDs(hostIDs []string, hosts engine.DispatcherHostProfiles,
+	tags map[string]string) (mHostIDs []string) {
+	if len(tags) == 0 {
+		return hostIDs
+	}
+	matching := make(utils.StringSet)
+	for _, host := range hosts {
+		if host.HasTags(tags) {
+			matching.Add(host.ID)
+		}
+	}
+	mHostIDs = make([]string, 0, len(matching))
+	for _, hostID := range hostIDs {
+		if matching.Has(hostID) {
+			mHostIDs = append(mHostIDs, hostID)
+		}
+	}
+	return
+}
+
+// exceptHostIDs keeps the order of hostIDs removing the excluded ones
+func exceptHostIDs(hostIDs []string, exclude utils.StringSet) (eHostIDs []string) {
+	if len(exclude) == 0 {
+		return hostIDs
+	}
+	eHostIDs = make([]string, 0, len(hostIDs))
+	for _, hostID := range hostIDs {
+		if !exclude.Has(hostID) {
+			eHostIDs = append(eHostIDs, hostID)
+		}
+	}
+	return
+}
+
+// preferHostIDs moves the preferred hosts in front of hostIDs, in the order they are given,
+// keeping the order of the others. The preferred hosts not part of hostIDs are ignored and
+// used is false if none of them is, in which case the order is not changed
+func preferHostIDs(hostIDs []string, preferred []string) (pHostIDs []string, used bool) {
+	pool := utils.NewStringSet(hostIDs)
+	moved := make(utils.StringSet)
+	for _, hostID := range preferred {
+		if !pool.Has(hostID) || moved.Has(hostID) {
+			continue
+		}
+		if pHostIDs == nil {
+			pHostIDs = make([]string, 0, len(hostIDs))
+		}
+		pHostIDs = append(pHostIDs, hostID)
+		moved.Add(hostID)
+	}
+	if len(pHostIDs) == 0 {
+		return hostIDs, false
+	}
+	for _, hostID := range hostIDs {
+		if !moved.Has(hostID) {
+			pHostIDs = append(pHostIDs, hostID)
+		}
+	}
+	return pHostIDs, true
+}
+
+// validateHosts checks the hosts shared by all the strategies: at least one host, each ID only once
+func validateHosts(hosts engine.DispatcherHostProfiles) error {
+	if len(hosts) == 0 {
+		return fmt.Errorf("no hosts")
+	}
+	if _, dupIDs := uniqueHosts(hosts); len(dupIDs) != 0 {
+		return fmt.Errorf("duplicated hosts: %q", dupIDs)
+	}
+	for _, host := range hosts {
+		if _, err := host.Priority(); err != nil {
+			return fmt.Errorf("invalid %s for host %q: %v", utils.MetaPriority, host.ID, err)
+		}
+	}
+	return nil
+}
+
+// dispatcherHosts returns a copy of the sorted profile hosts without the duplicated IDs, logging them
+func dispatcherHosts(pfl *engine.DispatcherProfile) (hosts engine.DispatcherHostProfiles) {
+	var dupIDs []string
+	if hosts, dupIDs = uniqueHosts(pfl.Hosts); len(dupIDs) != 0 {
+		utils.Logger.Warning(fmt.Sprintf("<%s> ignoring duplicated hosts %q within profile %q",
+			utils.DispatcherS, dupIDs, pfl.TenantID()))
+	}
+	return
+}
+
+// uniqueHosts returns a copy of the sorted hosts with only the first occurrence of each ID,
+// the one with the best priority and weight, so the hosts can be safely indexed by ID
+func uniqueHosts(hosts engine.DispatcherHostProfiles) (uHosts engine.DispatcherHostProfiles, dupIDs []string) {
+	uHosts = make(engine.DispatcherHostProfiles, 0, len(hosts))
+	seen := make(utils.StringSet)
+	for _, host := range hosts {
+		if seen.Has(host.ID) {
+			dupIDs = append(dupIDs, host.ID)
+			continue
+		}
+		seen.Add(host.ID)
+		uHosts = append(uHosts, host.Clone())
+	}
+	return
+}
+
+// selfHostLast moves the host of this engine, configured with self_host_id, at the end of hostIDs
+// so the dispatcher routes to itself only if none of the other hosts answers
+func selfHostLast(hostIDs []string) []string {
+	return lastHostID(hostIDs, config.CgrConfig().DispatcherSCfg().SelfHostID)
+}
+
+// lastHostID returns a copy of hostIDs with lastID moved at the end
+func lastHostID(hostIDs []string, lastID string) []string {
+	if lastID == utils.EmptyString || len(hostIDs) < 2 {
+		return hostIDs
+	}
+	lHostIDs := make([]string, 0, len(hostIDs))
+	var has bool
+	for _, hostID := range hostIDs {
+		if hostID == lastID {
+			has = true
+			continue
+		}
+		lHostIDs = append(lHostIDs, hostID)
+	}
+	if !has {
+		return hostIDs
+	}
+	return append(lHostIDs, lastID)
+}
+
+// firstHostIDs returns the first n hostIDs, all of them if there are less than n
+func firstHostIDs(hostIDs []string, n int) []string {
+	if n < 0 {
+		n = 0
+	}
+	if n < len(hostIDs) {
+		hostIDs = hostIDs[:n]
+	}
+	return hostIDs
+}
+
+// weightedOrder returns all the host IDs once, in the order of repeated random draws without replacement,
+// each draw picking one of the remaining hosts with a probability proportional to its weight,
+// so the heavier hosts tend to come first. The priority tiers are not considered and
+// the hosts without a positive weight come last, in random order
+func weightedOrder(hosts engine.DispatcherHostProfiles) (hostIDs []string) {
+	hostIDs = make([]string, 0, len(hosts))
+	weighted := make(engine.DispatcherHostProfiles, 0, len(hosts))
+	var unweighted engine.DispatcherHostProfiles
+	var total float64
+	for _, host := range hosts {
+		if host.Weight <= 0 {
+			unweighted = append(unweighted, host)
+			continue
+		}
+		weighted = append(weighted, host)
+		total += host.Weight
+	}
+	for len(weighted) != 0 {
+		i := len(weighted) - 1 // fallback for the rounding errors of total
+		for r, j := rand.Float64()*total, 0; j < len(weighted); j++ {
+			if r -= weighted[j].Weight; r < 0 {
+				i = j
+				break
+			}
+		}
+		hostIDs = append(hostIDs, weighted[i].ID)
+		total -= weighted[i].Weight
+		weighted[i] = weighted[len(weighted)-1]
+		weighted = weighted[:len(weighted)-1]
+	}
+	unweighted.Shuffle()
+	return append(hostIDs, unweighted.HostIDs()...)
+}
+
+// cachedRoute returns the host previously discovered for the routeID, nil if there is none
+// the route can be shared by profiles with other hosts so a host outside hostIDs is ignored
+// as is the self host while there are other hosts, letting the route be discovered again
+func cachedRoute(routeID string, hostIDs []string) (dH *engine.DispatcherHost) {
+	x, ok := engine.Cache.Get(utils.CacheDispatcherRoutes, routeID)
+	if !ok || x == nil {
+		return
+	}
+	if dH = x.(*engine.DispatcherHost); !utils.IsSliceMember(hostIDs, dH.ID) {
+		return nil
+	}
+	if len(hostIDs) > 1 && dH.ID == config.CgrConfig().DispatcherSCfg().SelfHostID {
+		return nil
+	}
+	return
+}
+
+type singleResultstrategyDispatcher struct {
+	depths *failoverDepths
+	dp     *dispatcherProfile // describes the decisions passed to the audit hook
+}
+
+func (sd *singleResultstrategyDispatcher) dispatch(dm *engine.DataManager, ev *utils.CGREvent, routeID *string, subsystem, tnt string,
+	hostIDs []string, serviceMethod string, args interface{}, reply interface{}) (err error) {
+	var dH *engine.DispatcherHost
+	var failed []string // the attempts ending with network errors
+	if routeID != nil && *routeID != "" {
+		// overwrite routeID with RouteID:Subsystem
+		*routeID = utils.ConcatenatedKey(*routeID, subsystem)
+		// use previously discovered route
+		if dH = cachedRoute(*routeID, hostIDs); dH != nil {
+			if err = dH.Call(serviceMethod, args, reply); !utils.IsNetworkError(err) {
+				sd.depths.record(0)
+				sd.dp.audit(ev, routeID, dH.ID, nil, err)
+				return
+			}
+			failed = append(failed, dH.ID)
+		}
+	}
+	for _, hostID := range selfHostLast(hostIDs) {
+		if dH, err = dm.GetDispatcherHost(tnt, hostID, true, true, utils.NonTransactional); err != nil {
+			err = utils.NewErrDispatcherS(err)
+			sd.dp.audit(ev, routeID, utils.EmptyString, failed, err)
+			return
+		}
+		if err = dH.Call(serviceMethod, args, reply); utils.IsNetworkError(err) {
+			failed = append(failed, hostID)
+			continue
+		}
+		sd.depths.record(len(failed)) // the failed attempts, the cached route included
+		sd.dp.audit(ev, routeID, hostID, failed, err)
+		if routeID != nil && *routeID != "" { // cache the discovered route
+			err = engine.Cache.Set(utils.CacheDispatcherRoutes, *routeID, dH,
+				nil, true, utils.EmptyString)
+		}
+		return
+	}
+	sd.dp.audit(ev, routeID, utils.EmptyString, failed, err) // no host answered
+	return
+}
+
+// newBrodcastStrategyDispatcher constructs the broadcast strategy out of the profile Strategy and StrategyParams
+func newBrodcastStrategyDispatcher(strategy string, params map[string]interface{}) (bs *brodcastStrategyDispatcher, err error) {
+	bs = &brodcastStrategyDispatcher{
+		strategy: strategy,
+		async:    strategy == utils.MetaBroadcastAsync,
+	}
+	if qrm, has := params[utils.MetaQuorum]; has {
+		var quorum int64
+		if quorum, err = utils.IfaceAsTInt64(qrm); err != nil {
+			return nil, err
+		}
+		if quorum < 0 {
+			return nil, fmt.Errorf("invalid %s: <%d>", utils.MetaQuorum, quorum)
+		}
+		bs.quorum = int(quorum)
+	}
+	return
+}
+
+// brodcastStrategyDispatcher calls all the hosts
+// with quorum set, reaching it is enough for the call to be considered successful, otherwise ErrQuorumFailed is returned
+type brodcastStrategyDispatcher struct {
+	strategy     string
+	async        bool // fire and forget, the calls are not waited for
+	quorum       int  // 0 means all the hosts need to succeed
+	degradations *strategyDegradations
+}
+
+// mode returns the broadcast mode of the strategy
+func (bs *brodcastStrategyDispatcher) mode() string {
+	if bs.async {
+		return utils.MetaBroadcastAsync
+	}
+	return utils.MetaBroadcastSync
+}
+
+func (bs *brodcastStrategyDispatcher) dispatch(dm *engine.DataManager, ev *utils.CGREvent, routeID *string, subsystem, tnt string, hostIDs []string,
+	serviceMethod string, args interface{}, reply interface{}) (err error) {
+	if bs.async {
+		rplyType := reflect.TypeOf(reply)
+		// the hosts are called one after the other since the internal subsystems can write to args
+		go func() {
+			for _, hostID := range hostIDs {
+				// every call gets its own reply since nobody waits to read it,
+				// a *string one without reply as for most of the calls not waiting for an answer
+				var hostReply interface{} = new(string)
+				if rplyType != nil && rplyType.Kind() == reflect.Ptr {
+					hostReply = reflect.New(rplyType.Elem()).Interface()
+				}
+				bs.callHostAsync(dm, tnt, hostID, serviceMethod, args, hostReply)
+			}
+		}()
+		if rply, canCast := reply.(*string); canCast && rply != nil {
+			*rply = utils.OK // the calls were fired
+		}
+		return
+	}
+	var hasErrors bool
+	if bs.quorum > len(hostIDs) {
+		bs.degradations.degraded(DegradedQuorumOverHosts)
+	}
+	qrm := newQuorumTracker(bs.quorum, len(hostIDs))
+	for _, hostID := range hostIDs {
+		var dH *engine.DispatcherHost
+		if dH, err = dm.GetDispatcherHost(tnt, hostID, true, true, utils.NonTransactional); err != nil {
+			if bs.quorum == 0 {
+				err = utils.NewErrDispatcherS(err)
+				return
+			}
+			// with quorum a missing host is one of the failures the quorum tolerates
+			utils.Logger.Err(fmt.Sprintf("<%s> error: <%s> at %s strategy for hostID %q",
+				utils.DispatcherS, err.Error(), bs.strategy, hostID))
+			qrm.report(false)
+			continue
+		}
+		if err = bs.callHost(dH, serviceMethod, args, reply); err != nil {
+			hasErrors = true
+		}
+		qrm.report(err == nil)
+	}
+	if bs.quorum != 0 {
+		if qrm.status() != QuorumReached {
+			return utils.ErrQuorumFailed
+		}
+		return nil
+	}
+	if hasErrors { // rewrite err if not all call were succesfull
+		return utils.ErrPartiallyExecuted
+	}
+	return
+}
+
+// callHostAsync calls one of the hosts in background, logging the errors since nobody waits for them
+func (bs *brodcastStrategyDispatcher) callHostAsync(dm *engine.DataManager, tnt, hostID string,
+	serviceMethod string, args interface{}, reply interface{}) {
+	dH, err := dm.GetDispatcherHost(tnt, hostID, true, true, utils.NonTransactional)
+	if err != nil {
+		utils.Logger.Err(fmt.Sprintf("<%s> error: <%s> at %s strategy for hostID %q",
+			utils.DispatcherS, err.Error(), bs.strategy, hostID))
+		return
+	}
+	bs.callHost(dH, serviceMethod, args, reply)
+}
+
+// callHost calls one of the hosts, logging the errors
+func (bs *brodcastStrategyDispatcher) callHost(dH *engine.DispatcherHost,
+	serviceMethod string, args interface{}, reply interface{}) (err error) {
+	if err = dH.Call(serviceMethod, args, reply); utils.IsNetworkError(err) {
+		utils.Logger.Err(fmt.Sprintf("<%s> network error: <%s> at %s strategy for hostID %q",
+			utils.DispatcherS, err.Error(), bs.strategy, dH.ID))
+	} else if err != nil {
+		utils.Logger.Err(fmt.Sprintf("<%s> error: <%s> at %s strategy for hostID %q",
+			utils.DispatcherS, err.Error(), bs.strategy, dH.ID))
+	}
+	return
+}
+
+// Quorum states reported when broadcasting with quorum
+const (
+	QuorumPending = "QuorumPending"
+	QuorumReached = "QuorumReached"
+	QuorumFailed  = "QuorumFailed"
+)
+
+// newQuorumTracker constructs a quorumTracker for total hosts
+// a quorum of 0 or higher than total means all hosts need to succeed
+func newQuorumTracker(quorum, total int) *quorumTracker {
+	if quorum <= 0 || quorum > total {
+		quorum = total
+	}
+	return &quorumTracker{quorum: quorum, total: total}
+}
+
+// quorumTracker follows the results of a broadcast to decide on quorum
+type quorumTracker struct {
+	quorum    int
+	total     int
+	successes int
+	failures  int
+}
+
+// report registers the result of one host call, returning the resulting quorum status
+func (qt *quorumTracker) report(success bool) string {
+	if success {
+		qt.successes++
+	} else {
+		qt.failures++
+	}
+	return qt.status()
+}
+
+// status returns QuorumReached once enough hosts succeeded
+// or QuorumFailed as soon as the remaining hosts cannot reach quorum anymore
+func (qt *quorumTracker) status() string {
+	if qt.successes >= qt.quorum {
+		return QuorumReached
+	}
+	if qt.total-qt.failures < qt.quorum {
+		return QuorumFailed
+	}
+	return QuorumPending
+}
+
+func newLoadStrategyDispatcher(hosts engine.DispatcherHostProfiles, tntID string) (ls *loadStrategyDispatcher, err error) {
+	ls = &loadStrategyDispatcher{
+		tntID: tntID,
+		hosts: hosts,
+		prios: hostPriorities(hosts),
+	}
+
+	return
+}
+
+type loadStrategyDispatcher struct {
+	sync.RWMutex
+	tntID        string
+	hosts        engine.DispatcherHostProfiles
+	prios        map[string]int // priority tier per host ID, the load orders the hosts inside their tier
+	tieBreak     *tieBreaker    // orders the hosts with equal load
+	degradations *strategyDegradations
+	depths       *failoverDepths
+	dp           *dispatcherProfile // describes the decisions passed to the audit hook
+}
+
+func newLoadMetrics(hosts engine.DispatcherHostProfiles) (*LoadMetrics, error) {
+	lM := &LoadMetrics{
+		HostsLoad:  make(map[string]int64),
+		HostsRatio: make(map[string]int64),
+		SumRatio:   0,
+	}
+	for _, host := range hosts {
+		ratio, err := hostRatio(host)
+		if err != nil {
+			return nil, err
+		}
+		lM.HostsRatio[host.ID] = ratio
+		lM.SumRatio += ratio
+	}
+	return lM, nil
+}
+
+type LoadMetrics struct {
+	mutex      sync.RWMutex
+	HostsLoad  map[string]int64
+	HostsRatio map[string]int64
+	SumRatio   int64
+}
+
+func (ld *loadStrategyDispatcher) dispatch(dm *engine.DataManager, ev *utils.CGREvent, routeID *string, subsystem, tnt string, hostIDs []string,
+	serviceMethod string, args interface{}, reply interface{}) (err error) {
+	var dH *engine.DispatcherHost
+	var lM *LoadMetrics
+	var failed []string // the attempts ending with network errors
+	if x, ok := engine.Cache.Get(utils.CacheDispatcherLoads, ld.tntID); ok && x != nil {
+		var canCast bool
+		if lM, canCast = x.(*LoadMetrics); !canCast {
+			return fmt.Errorf("cannot cast %+v to *LoadMetrics", x)
+		}
+	} else {
+		ld.RLock()
+		lM, err = newLoadMetrics(ld.hosts)
+		ld.RUnlock()
+		if err != nil {
+			return
+		}
+	}
+
+	if routeID != nil && *routeID != "" {
+		// overwrite routeID with RouteID:Subsystem
+		*routeID = utils.ConcatenatedKey(*routeID, subsystem)
+		// use previously discovered route
+		if dH = cachedRoute(*routeID, hostIDs); dH != nil {
+			lM.incrementLoad(dH.ID, ld.tntID)
+			err = dH.Call(serviceMethod, args, reply)
+			lM.decrementLoad(dH.ID, ld.tntID) // call ended
+			if !utils.IsNetworkError(err) {
+				ld.depths.record(0)
+				ld.dp.audit(ev, routeID, dH.ID, nil, err)
+				return
+			}
+			failed = append(failed, dH.ID)
+		}
+	}
+	for _, hostID := range selfHostLast(ld.hostIDs(lM, hostIDs)) {
+		if dH, err = dm.GetDispatcherHost(tnt, hostID, true, true, utils.NonTransactional); err != nil {
+			err = utils.NewErrDispatcherS(err)
+			ld.dp.audit(ev, routeID, utils.EmptyString, failed, err)
+			return
+		}
 		lM.incrementLoad(hostID, ld.tntID)
 		err = dH.Call(serviceMethod, args, reply)
 		lM.decrementLoad(hostID, ld.tntID) // call ended
 		if utils.IsNetworkError(err) {
+			failed = append(failed, hostID)
 			continue
 		}
+		ld.depths.record(len(failed)) // the failed attempts, the cached route included
+		ld.dp.audit(ev, routeID, hostID, failed, err)
 		if routeID != nil && *routeID != "" { // cache the discovered route
-			if err = engine.Cache.Set(utils.CacheDispatcherRoutes, *routeID, dH,
-				nil, true, utils.EmptyString); err != nil {
-				return
-			}
+			err = engine.Cache.Set(utils.CacheDispatcherRoutes, *routeID, dH,
+				nil, true, utils.EmptyString)
 		}
-		break
+		return
 	}
+	ld.dp.audit(ev, routeID, utils.EmptyString, failed, err) // no host answered
 	return
 }
 
-func (lM *LoadMetrics) getHosts(hostIDs []string) []string {
-	costs := make([]int64, len(hostIDs))
+// setProfile refreshes the hosts and, when updateTieBreak is true, the tie break policy
+// after the profile changed, keeping the *round_robin rotation if the policy is the same
+func (ld *loadStrategyDispatcher) setProfile(hosts engine.DispatcherHostProfiles, tb *tieBreaker, updateTieBreak bool) {
+	ld.Lock()
+	ld.hosts = hosts
+	ld.prios = hostPriorities(hosts)
+	if updateTieBreak && ld.tieBreak.policy() != tb.policy() {
+		ld.tieBreak = tb
+	}
+	ld.Unlock()
+	// the cached metrics hold the ratios of the previous hosts
+	if err := engine.Cache.Remove(utils.CacheDispatcherLoads, ld.tntID,
+		true, utils.NonTransactional); err != nil {
+		utils.Logger.Warning(fmt.Sprintf("<%s> error: <%s> removing the cached load metrics for profile %q",
+			utils.DispatcherS, err.Error(), ld.tntID))
+	}
+}
+
+// hostIDs orders the hosts based on their load
+func (ld *loadStrategyDispatcher) hostIDs(lM *LoadMetrics, hostIDs []string) []string {
+	if lM.allLoaded(hostIDs) {
+		ld.degradations.degraded(DegradedAllHostsLoaded)
+	}
+	ld.RLock()
+	tb := ld.tieBreak
+	prios := ld.prios
+	ld.RUnlock()
+	if tb == nil {
+		return lM.getHosts(hostIDs, prios)
+	}
+	costs := lM.hostCosts(hostIDs)
+	hostIDs = lM.getHosts(hostIDs, prios)
+	tb.breakTies(hostIDs, func(i, j int) bool {
+		return prios[hostIDs[i]] == prios[hostIDs[j]] &&
+			costs[hostIDs[i]] == costs[hostIDs[j]]
+	})
+	return hostIDs
+}
+
+// hostPriorities returns the priority tier of each host, an invalid *priority being considered 0 as on sorting
+func hostPriorities(hosts engine.DispatcherHostProfiles) (prios map[string]int) {
+	prios = make(map[string]int, len(hosts))
+	for _, host := range hosts {
+		prios[host.ID], _ = host.Priority()
+	}
+	return
+}
+
+// allLoaded returns true if all the hosts reached their ratio so the load does not decide anymore
+func (lM *LoadMetrics) allLoaded(hostIDs []string) bool {
+	if len(hostIDs) == 0 {
+		return false
+	}
 	lM.mutex.RLock()
-	for i, id := range hostIDs {
-		costs[i] = lM.HostsLoad[id]
-		if costs[i] >= lM.HostsRatio[id] {
-			costs[i] += lM.SumRatio
+	defer lM.mutex.RUnlock()
+	for _, id := range hostIDs {
+		if lM.HostsLoad[id] < lM.HostsRatio[id] {
+			return false
 		}
 	}
+	return true
+}
+
+// hostCosts returns the cost of sending one more request to each host
+func (lM *LoadMetrics) hostCosts(hostIDs []string) (costs map[string]int64) {
+	costs = make(map[string]int64, len(hostIDs))
+	lM.mutex.RLock()
+	for _, id := range hostIDs {
+		cost := lM.HostsLoad[id]
+		if cost >= lM.HostsRatio[id] {
+			cost += lM.SumRatio
+		}
+		costs[id] = cost
+	}
 	lM.mutex.RUnlock()
-	sort.Slice(hostIDs, func(i, j int) bool {
-		return costs[i] < costs[j]
+	return
+}
+
+// getHosts sorts the hostIDs based on their priority tier then on their cost inside the tier,
+// keeping the initial order for equal costs, so a higher tier is used only after the lower ones
+func (lM *LoadMetrics) getHosts(hostIDs []string, prios map[string]int) []string {
+	costs := lM.hostCosts(hostIDs)
+	sort.SliceStable(hostIDs, func(i, j int) bool {
+		if iPrio, jPrio := prios[hostIDs[i]], prios[hostIDs[j]]; iPrio != jPrio {
+			return iPrio < jPrio
+		}
+		return costs[hostIDs[i]] < costs[hostIDs[j]]
 	})
 	return hostIDs
 }
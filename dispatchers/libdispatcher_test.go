@@ -0,0 +1,2443 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package dispatchers
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+	"github.com/cgrates/rpcclient"
+)
+
+func TestLibDispatcherHostIDsMatching(t *testing.T) {
+	pfl := &engine.DispatcherProfile{
+		Tenant:   "cgrates.org",
+		ID:       "DSP_TAGS",
+		Strategy: utils.MetaWeight,
+		Hosts: engine.DispatcherHostProfiles{
+			{ID: "DSP_1", Weight: 30, Params: map[string]interface{}{"version": "1.0", "region": "eu"}},
+			{ID: "DSP_2", Weight: 20, Params: map[string]interface{}{"version": "2.0", "region": "eu"}},
+			{ID: "DSP_3", Weight: 10, Params: map[string]interface{}{"version": "1.0", "region": "us", "gpu": "true"}},
+			{ID: "DSP_4", Weight: 5},
+		},
+	}
+	d, err := newDispatcher(nil, pfl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		tags map[string]string
+		exp  []string
+	}{
+		{nil, []string{"DSP_1", "DSP_2", "DSP_3", "DSP_4"}},
+		{map[string]string{"region": "eu"}, []string{"DSP_1", "DSP_2"}},
+		{map[string]string{"version": "1.0"}, []string{"DSP_1", "DSP_3"}},
+		{map[string]string{"version": "1.0", "region": "eu"}, []string{"DSP_1"}},
+		{map[string]string{"gpu": "true"}, []string{"DSP_3"}},
+		{map[string]string{"version": "2.0", "region": "us"}, []string{}},
+	} {
+		if rply := HostIDsMatching(d, tc.tags); !reflect.DeepEqual(tc.exp, rply) {
+			t.Errorf("for tags %+v expecting: %+v, received: %+v", tc.tags, tc.exp, rply)
+		}
+	}
+}
+
+func TestLibDispatcherHostIDsMatchingRoundRobin(t *testing.T) {
+	pfl := &engine.DispatcherProfile{
+		Tenant:   "cgrates.org",
+		ID:       "DSP_TAGS",
+		Strategy: utils.MetaRoundRobin,
+		Hosts: engine.DispatcherHostProfiles{
+			{ID: "DSP_1", Weight: 30, Params: map[string]interface{}{"region": "eu"}},
+			{ID: "DSP_2", Weight: 20, Params: map[string]interface{}{"region": "us"}},
+			{ID: "DSP_3", Weight: 10, Params: map[string]interface{}{"region": "eu"}},
+		},
+	}
+	d, err := newDispatcher(nil, pfl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tags := map[string]string{"region": "eu"}
+	for _, exp := range [][]string{
+		{"DSP_1", "DSP_3"},
+		{"DSP_3", "DSP_1"},
+		{"DSP_3", "DSP_1"},
+		{"DSP_1", "DSP_3"},
+	} {
+		if rply := HostIDsMatching(d, tags); !reflect.DeepEqual(exp, rply) {
+			t.Errorf("expecting: %+v, received: %+v", exp, rply)
+		}
+	}
+}
+
+func TestLibDispatcherQuorumTrackerReached(t *testing.T) {
+	qrm := newQuorumTracker(2, 4)
+	if status := qrm.status(); status != QuorumPending {
+		t.Errorf("expecting: %s, received: %s", QuorumPending, status)
+	}
+	if status := qrm.report(false); status != QuorumPending {
+		t.Errorf("expecting: %s, received: %s", QuorumPending, status)
+	}
+	if status := qrm.report(true); status != QuorumPending {
+		t.Errorf("expecting: %s, received: %s", QuorumPending, status)
+	}
+	// reached before all the hosts have reported
+	if status := qrm.report(true); status != QuorumReached {
+		t.Errorf("expecting: %s, received: %s", QuorumReached, status)
+	}
+	if status := qrm.report(false); status != QuorumReached {
+		t.Errorf("expecting: %s, received: %s", QuorumReached, status)
+	}
+}
+
+func TestLibDispatcherQuorumTrackerFailed(t *testing.T) {
+	qrm := newQuorumTracker(3, 4)
+	if status := qrm.report(true); status != QuorumPending {
+		t.Errorf("expecting: %s, received: %s", QuorumPending, status)
+	}
+	if status := qrm.report(false); status != QuorumPending {
+		t.Errorf("expecting: %s, received: %s", QuorumPending, status)
+	}
+	// only one host left, quorum cannot be reached anymore
+	if status := qrm.report(false); status != QuorumFailed {
+		t.Errorf("expecting: %s, received: %s", QuorumFailed, status)
+	}
+}
+
+func TestLibDispatcherQuorumTrackerAll(t *testing.T) {
+	qrm := newQuorumTracker(0, 2)
+	if status := qrm.report(true); status != QuorumPending {
+		t.Errorf("expecting: %s, received: %s", QuorumPending, status)
+	}
+	if status := qrm.report(true); status != QuorumReached {
+		t.Errorf("expecting: %s, received: %s", QuorumReached, status)
+	}
+	qrm = newQuorumTracker(5, 2)
+	if status := qrm.report(false); status != QuorumFailed {
+		t.Errorf("expecting: %s, received: %s", QuorumFailed, status)
+	}
+}
+
+// testPingConn answers OK to all the calls of the hosts reachable over *internal
+type testPingConn struct{}
+
+func (testPingConn) Call(serviceMethod string, args interface{}, reply interface{}) error {
+	*reply.(*string) = utils.OK
+	return nil
+}
+
+// testDisconnectConn fails the next fails calls as disconnected and answers OK to the others
+type testDisconnectConn struct {
+	fails int
+}
+
+func (c *testDisconnectConn) Call(serviceMethod string, args interface{}, reply interface{}) error {
+	if c.fails > 0 {
+		c.fails--
+		return utils.ErrDisconnected
+	}
+	*reply.(*string) = utils.OK
+	return nil
+}
+
+// setTestInternalHosts caches the hosts as reachable over *internal through conn for the AttributeSv1 calls,
+// returning the function restoring the internal connections and clearing the cached hosts and routes
+func setTestInternalHosts(t *testing.T, conn rpcclient.ClientConnector, hostIDs ...string) (restore func()) {
+	intRPC := engine.IntRPC
+	engine.IntRPC = engine.NewRPCClientSet()
+	connChan := make(chan rpcclient.ClientConnector, 1)
+	connChan <- conn
+	engine.IntRPC.AddInternalRPCClient(utils.AttributeSv1, connChan)
+	for _, hostID := range hostIDs {
+		if err := engine.Cache.Set(utils.CacheDispatcherHosts, utils.ConcatenatedKey("cgrates.org", hostID),
+			&engine.DispatcherHost{
+				Tenant: "cgrates.org",
+				ID:     hostID,
+				Conns:  []*config.RemoteHost{{Address: utils.MetaInternal}},
+			}, nil, true, utils.EmptyString); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return func() {
+		engine.IntRPC = intRPC
+		engine.Cache.Clear([]string{utils.CacheDispatcherHosts, utils.CacheDispatcherRoutes})
+	}
+}
+
+func TestLibDispatcherAuditHook(t *testing.T) {
+	conn := &testDisconnectConn{fails: 1}
+	defer setTestInternalHosts(t, conn, "DSP_AUDIT_1", "DSP_AUDIT_2")()
+	var recs []AuditRecord
+	ah := new(auditHook)
+	ah.set(func(rec AuditRecord) { recs = append(recs, rec) })
+	d, err := newAuditedDispatcher(nil, &engine.DispatcherProfile{
+		Tenant:   "cgrates.org",
+		ID:       "DSP_AUDIT",
+		Strategy: utils.MetaWeight,
+		Hosts: engine.DispatcherHostProfiles{
+			{ID: "DSP_AUDIT_1", Weight: 20},
+			{ID: "DSP_AUDIT_2", Weight: 10},
+			{ID: "DSP_AUDIT_MISSING", Weight: 5}, // not cached and no DataManager to get it from
+		},
+	}, ah)
+	if err != nil {
+		t.Fatal(err)
+	}
+	routeID := utils.ConcatenatedKey("ROUTE_AUDIT", utils.MetaAttributes)
+	for i, tc := range []struct {
+		fails  int
+		expErr error
+		exp    AuditRecord
+	}{
+		{fails: 1, exp: AuditRecord{HostID: "DSP_AUDIT_2", Failover: true,
+			Skipped: []string{"DSP_AUDIT_1"}}},
+		// the discovered route is used for the next call
+		{exp: AuditRecord{HostID: "DSP_AUDIT_2"}},
+		// the cached route fails and answers again after DSP_AUDIT_1
+		{fails: 2, exp: AuditRecord{HostID: "DSP_AUDIT_2", Failover: true,
+			Skipped: []string{"DSP_AUDIT_1"}}},
+		// no host answers before the missing one
+		{fails: 3, expErr: utils.NewErrDispatcherS(utils.ErrNoDatabaseConn),
+			exp: AuditRecord{Failover: true, Skipped: []string{"DSP_AUDIT_2", "DSP_AUDIT_1"},
+				Error: utils.NewErrDispatcherS(utils.ErrNoDatabaseConn).Error()}},
+	} {
+		conn.fails = tc.fails
+		evID := fmt.Sprintf("EV_AUDIT_%d", i)
+		var reply string
+		if err := d.Dispatch(&utils.CGREvent{ID: evID}, &utils.ArgDispatcher{RouteID: utils.StringPointer("ROUTE_AUDIT")},
+			utils.MetaAttributes, utils.AttributeSv1Ping, &utils.CGREvent{}, &reply); !reflect.DeepEqual(tc.expErr, err) {
+			t.Fatalf("call %d expecting error: %v, received: %v", i, tc.expErr, err)
+		}
+		if len(recs) != i+1 {
+			t.Fatalf("expecting one record per call, received: %s", utils.ToJSON(recs))
+		}
+		exp := tc.exp
+		exp.Tenant, exp.ProfileID, exp.Strategy = "cgrates.org", "DSP_AUDIT", utils.MetaWeight
+		exp.EventID, exp.RouteID = evID, routeID
+		if recs[i].Time.IsZero() {
+			t.Errorf("expecting the time of the decision, received: %s", utils.ToJSON(recs[i]))
+		}
+		recs[i].Time = time.Time{}
+		if !reflect.DeepEqual(exp, recs[i]) {
+			t.Errorf("call %d expecting: %s, received: %s", i, utils.ToJSON(exp), utils.ToJSON(recs[i]))
+		}
+	}
+	// every host fails with network errors
+	recs = nil
+	ld, err := newAuditedDispatcher(nil, &engine.DispatcherProfile{
+		Tenant:   "cgrates.org",
+		ID:       "DSP_AUDIT_LOAD",
+		Strategy: utils.MetaLoad,
+		Hosts: engine.DispatcherHostProfiles{
+			{ID: "DSP_AUDIT_1", Weight: 20},
+			{ID: "DSP_AUDIT_2", Weight: 10},
+		},
+	}, ah)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.fails = 2
+	var reply string
+	if err := ld.Dispatch(nil, nil, utils.MetaAttributes, utils.AttributeSv1Ping,
+		&utils.CGREvent{}, &reply); err != utils.ErrDisconnected {
+		t.Fatalf("expecting error: %v, received: %v", utils.ErrDisconnected, err)
+	}
+	if len(recs) != 1 {
+		t.Fatalf("expecting one record, received: %s", utils.ToJSON(recs))
+	}
+	recs[0].Time = time.Time{}
+	if exp := (AuditRecord{Tenant: "cgrates.org", ProfileID: "DSP_AUDIT_LOAD", Strategy: utils.MetaLoad,
+		Failover: true, Skipped: []string{"DSP_AUDIT_1", "DSP_AUDIT_2"},
+		Error: utils.ErrDisconnected.Error()}); !reflect.DeepEqual(exp, recs[0]) {
+		t.Errorf("expecting: %s, received: %s", utils.ToJSON(exp), utils.ToJSON(recs[0]))
+	}
+	// the typed nil disables the auditing
+	ah.set(nil)
+	if err := d.Dispatch(nil, nil, utils.MetaAttributes, utils.AttributeSv1Ping,
+		&utils.CGREvent{}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if len(recs) != 1 {
+		t.Errorf("expecting no record without hook, received: %s", utils.ToJSON(recs[1:]))
+	}
+}
+
+func TestLibDispatcherBroadcastQuorum(t *testing.T) {
+	intRPC := engine.IntRPC
+	defer func() { engine.IntRPC = intRPC }()
+	engine.IntRPC = engine.NewRPCClientSet()
+	connChan := make(chan rpcclient.ClientConnector, 1)
+	connChan <- new(testPingConn)
+	engine.IntRPC.AddInternalRPCClient(utils.AttributeSv1, connChan)
+	// two reachable hosts and a missing one
+	for _, hostID := range []string{"DSP_QRM_1", "DSP_QRM_2"} {
+		if err := engine.Cache.Set(utils.CacheDispatcherHosts, utils.ConcatenatedKey("cgrates.org", hostID),
+			&engine.DispatcherHost{
+				Tenant: "cgrates.org",
+				ID:     hostID,
+				Conns:  []*config.RemoteHost{{Address: utils.MetaInternal}},
+			}, nil, true, utils.EmptyString); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := engine.Cache.Set(utils.CacheDispatcherHosts, "cgrates.org:DSP_QRM_3",
+		nil, nil, true, utils.EmptyString); err != nil {
+		t.Fatal(err)
+	}
+	defer engine.Cache.Clear([]string{utils.CacheDispatcherHosts})
+	newQuorumDispatcher := func(quorum int) Dispatcher {
+		d, err := newDispatcher(nil, &engine.DispatcherProfile{
+			Tenant:         "cgrates.org",
+			ID:             "DSP_QUORUM",
+			Strategy:       utils.MetaBroadcast,
+			StrategyParams: map[string]interface{}{utils.MetaQuorum: quorum},
+			Hosts: engine.DispatcherHostProfiles{
+				{ID: "DSP_QRM_1"},
+				{ID: "DSP_QRM_2"},
+				{ID: "DSP_QRM_3"},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+	var reply string
+	if err := newQuorumDispatcher(2).Dispatch(nil, nil, utils.MetaAttributes,
+		utils.AttributeSv1Ping, &utils.CGREvent{}, &reply); err != nil {
+		t.Error(err)
+	} else if reply != utils.OK {
+		t.Errorf("expecting: %s, received: %s", utils.OK, reply)
+	}
+	if err := newQuorumDispatcher(3).Dispatch(nil, nil, utils.MetaAttributes,
+		utils.AttributeSv1Ping, &utils.CGREvent{}, &reply); err != utils.ErrQuorumFailed {
+		t.Errorf("expecting: %v, received: %v", utils.ErrQuorumFailed, err)
+	}
+	// without quorum all the hosts are needed
+	if err := newQuorumDispatcher(0).Dispatch(nil, nil, utils.MetaAttributes,
+		utils.AttributeSv1Ping, &utils.CGREvent{}, &reply); err == nil ||
+		err.Error() != utils.NewErrDispatcherS(utils.ErrNotFound).Error() {
+		t.Errorf("expecting: %v, received: %v", utils.NewErrDispatcherS(utils.ErrNotFound), err)
+	}
+}
+
+func TestLibDispatcherNewBrodcastStrategyDispatcher(t *testing.T) {
+	if bs, err := newBrodcastStrategyDispatcher(utils.MetaBroadcast, nil); err != nil {
+		t.Error(err)
+	} else if bs.quorum != 0 {
+		t.Errorf("expecting: 0, received: %+v", bs.quorum)
+	}
+	if bs, err := newBrodcastStrategyDispatcher(utils.MetaBroadcast, map[string]interface{}{utils.MetaQuorum: "2"}); err != nil {
+		t.Error(err)
+	} else if bs.quorum != 2 {
+		t.Errorf("expecting: 2, received: %+v", bs.quorum)
+	}
+	if _, err := newBrodcastStrategyDispatcher(utils.MetaBroadcast, map[string]interface{}{utils.MetaQuorum: "two"}); err == nil {
+		t.Error("expecting error for invalid quorum")
+	}
+	if _, err := newBrodcastStrategyDispatcher(utils.MetaBroadcast, map[string]interface{}{utils.MetaQuorum: -1}); err == nil {
+		t.Error("expecting error for negative quorum")
+	}
+	if _, err := newDispatcher(nil, &engine.DispatcherProfile{
+		Tenant:         "cgrates.org",
+		ID:             "DSP_QUORUM",
+		Strategy:       utils.MetaBroadcast,
+		StrategyParams: map[string]interface{}{utils.MetaQuorum: "two"},
+	}); err == nil {
+		t.Error("expecting error for invalid quorum")
+	}
+}
+
+func TestLibDispatcherRandomEventIDSeed(t *testing.T) {
+	pfl := &engine.DispatcherProfile{
+		Tenant:         "cgrates.org",
+		ID:             "DSP_SEED",
+		Strategy:       utils.MetaRandom,
+		StrategyParams: map[string]interface{}{utils.MetaEventIDSeed: true},
+		Hosts: engine.DispatcherHostProfiles{
+			{ID: "DSP_1"},
+			{ID: "DSP_2"},
+			{ID: "DSP_3"},
+		},
+	}
+	d, err := newDispatcher(nil, pfl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rd := d.(*RandomDispatcher)
+	if !rd.idSeed {
+		t.Fatal("expecting the event ID seed to be enabled")
+	}
+	exp := rd.hostIDsForEventID("EV_1")
+	for i := 0; i < 10; i++ {
+		if rply := rd.hostIDsForEventID("EV_1"); !reflect.DeepEqual(exp, rply) {
+			t.Errorf("expecting: %+v, received: %+v", exp, rply)
+		}
+	}
+	if err := checkDistribution(func() string {
+		return rd.hostIDsForEventID(utils.GenUUID())[0]
+	}, 3000, map[string]float64{"DSP_1": 1. / 3, "DSP_2": 1. / 3, "DSP_3": 1. / 3}, 0.05); err != nil {
+		t.Errorf("uneven distribution: %v", err)
+	}
+	if _, err := newDispatcher(nil, &engine.DispatcherProfile{
+		Tenant:         "cgrates.org",
+		ID:             "DSP_SEED",
+		Strategy:       utils.MetaRandom,
+		StrategyParams: map[string]interface{}{utils.MetaEventIDSeed: "maybe"},
+	}); err == nil {
+		t.Error("expecting error for invalid seed option")
+	}
+}
+
+func TestLibDispatcherSelectionLatency(t *testing.T) {
+	pfl := &engine.DispatcherProfile{
+		Tenant:   "cgrates.org",
+		ID:       "DSP_LATENCY",
+		Strategy: utils.MetaRoundRobin,
+		Hosts: engine.DispatcherHostProfiles{
+			{ID: "DSP_1"},
+			{ID: "DSP_2"},
+		},
+	}
+	d, err := newDispatcher(nil, pfl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.(*RoundRobinDispatcher).selectionLatency != nil {
+		t.Error("expecting the latency recording to be disabled by default")
+	}
+	dispatch := func() {
+		// without a DataManager the call fails after the hosts were ordered
+		if err := d.Dispatch(nil, nil, utils.MetaAttributes, utils.AttributeSv1Ping,
+			new(utils.CGREvent), nil); err == nil || !strings.Contains(err.Error(), utils.ErrNoDatabaseConn.Error()) {
+			t.Errorf("expecting: %v, received: %v", utils.ErrNoDatabaseConn, err)
+		}
+	}
+	dispatch()
+	if p50, p95, p99 := d.SelectionLatency(); p50 != 0 || p95 != 0 || p99 != 0 {
+		t.Errorf("expecting no latency, received: %v %v %v", p50, p95, p99)
+	}
+	pfl.StrategyParams = map[string]interface{}{utils.MetaSelectionLatency: true}
+	if d, err = newDispatcher(nil, pfl); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100; i++ {
+		dispatch()
+	}
+	if samples := len(d.(*RoundRobinDispatcher).selectionLatency.samples); samples != 100 {
+		t.Errorf("expecting one sample per dispatch, received: %d", samples)
+	}
+	p50, p95, p99 := d.SelectionLatency()
+	if p50 <= 0 || p95 < p50 || p99 < p95 {
+		t.Errorf("unexpected percentiles: %v %v %v", p50, p95, p99)
+	}
+}
+
+func TestLibDispatcherSelectionLatencyPercentiles(t *testing.T) {
+	sl := &selectionLatency{samples: make([]time.Duration, 0, selectionLatencySamples)}
+	for i := selectionLatencySamples + 100; i > 0; i-- { // overwrite the oldest samples
+		sl.recordSelection(time.Now())
+	}
+	if len(sl.samples) != selectionLatencySamples {
+		t.Errorf("expecting: %d samples, received: %d", selectionLatencySamples, len(sl.samples))
+	}
+	sl.samples = make([]time.Duration, 100)
+	for i := range sl.samples {
+		sl.samples[i] = time.Duration(100-i) * time.Millisecond
+	}
+	if p50, p95, p99 := sl.SelectionLatency(); p50 != 50*time.Millisecond ||
+		p95 != 95*time.Millisecond || p99 != 99*time.Millisecond {
+		t.Errorf("unexpected percentiles: %v %v %v", p50, p95, p99)
+	}
+	if _, err := newSelectionLatency(map[string]interface{}{utils.MetaSelectionLatency: "maybe"}); err == nil {
+		t.Error("expecting error for invalid option")
+	}
+}
+
+func TestLibDispatcherBroadcastMode(t *testing.T) {
+	for strategy, expMode := range map[string]string{
+		utils.MetaBroadcast:      utils.MetaBroadcastSync,
+		utils.MetaBroadcastSync:  utils.MetaBroadcastSync,
+		utils.MetaBroadcastAsync: utils.MetaBroadcastAsync,
+	} {
+		d, err := newDispatcher(nil, &engine.DispatcherProfile{
+			Tenant:   "cgrates.org",
+			ID:       "DSP_BROADCAST",
+			Strategy: strategy,
+			Hosts: engine.DispatcherHostProfiles{
+				{ID: "DSP_1", Weight: 10},
+				{ID: "DSP_2", Weight: 20},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		bd, canCast := d.(*BroadcastDispatcher)
+		if !canCast {
+			t.Fatalf("expecting *BroadcastDispatcher for %s, received: %T", strategy, d)
+		}
+		if mode := bd.BroadcastMode(); mode != expMode {
+			t.Errorf("for %s expecting: %s, received: %s", strategy, expMode, mode)
+		}
+		if hostIDs := bd.HostIDs(); !reflect.DeepEqual([]string{"DSP_2", "DSP_1"}, hostIDs) {
+			t.Errorf("for %s expecting: %+v, received: %+v", strategy, []string{"DSP_2", "DSP_1"}, hostIDs)
+		}
+	}
+	d, err := newDispatcher(nil, &engine.DispatcherProfile{
+		Tenant:   "cgrates.org",
+		ID:       "DSP_BROADCAST",
+		Strategy: utils.MetaBroadcastSync,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	intRPC := engine.IntRPC
+	defer func() { engine.IntRPC = intRPC }()
+	engine.IntRPC = engine.NewRPCClientSet()
+	conn := &testNotifyConn{calls: make(chan string, 10)}
+	connChan := make(chan rpcclient.ClientConnector, 1)
+	connChan <- conn
+	engine.IntRPC.AddInternalRPCClient(utils.AttributeSv1, connChan)
+	for _, hostID := range []string{"DSP_ASYNC_1", "DSP_ASYNC_2", "DSP_ASYNC_3"} {
+		if err := engine.Cache.Set(utils.CacheDispatcherHosts, utils.ConcatenatedKey("cgrates.org", hostID),
+			&engine.DispatcherHost{
+				Tenant: "cgrates.org",
+				ID:     hostID,
+				Conns:  []*config.RemoteHost{{Address: utils.MetaInternal}},
+			}, nil, true, utils.EmptyString); err != nil {
+			t.Fatal(err)
+		}
+	}
+	defer engine.Cache.Clear([]string{utils.CacheDispatcherHosts})
+	d.SetProfile(&engine.DispatcherProfile{
+		Tenant:   "cgrates.org",
+		ID:       "DSP_BROADCAST",
+		Strategy: utils.MetaBroadcastAsync,
+		Hosts:    engine.DispatcherHostProfiles{{ID: "DSP_ASYNC_1"}, {ID: "DSP_ASYNC_2"}, {ID: "DSP_ASYNC_3"}},
+	})
+	if mode := d.(*BroadcastDispatcher).BroadcastMode(); mode != utils.MetaBroadcastAsync {
+		t.Errorf("expecting: %s, received: %s", utils.MetaBroadcastAsync, mode)
+	}
+	// each call reaches all the hosts, one host at a time
+	waitHosts := func() {
+		for i := 0; i < 3; i++ {
+			select {
+			case <-conn.calls:
+			case <-time.After(time.Second):
+				t.Fatalf("expecting 3 calls to the hosts, received: %d", i)
+			}
+		}
+	}
+	var reply string
+	if err := d.Dispatch(nil, nil, utils.MetaAttributes, utils.AttributeSv1Ping,
+		new(utils.CGREvent), &reply); err != nil {
+		t.Error(err)
+	} else if reply != utils.OK {
+		t.Errorf("expecting: %s, received: %q", utils.OK, reply)
+	}
+	waitHosts()
+	if err := d.Dispatch(nil, nil, utils.MetaAttributes, utils.AttributeSv1Ping,
+		new(utils.CGREvent), nil); err != nil {
+		t.Error(err)
+	}
+	waitHosts()
+	if overlaps := atomic.LoadInt32(&conn.overlaps); overlaps != 0 {
+		t.Errorf("expecting the hosts of a call to be called one after the other, received %d overlaps", overlaps)
+	}
+}
+
+// testNotifyConn answers OK to the calls, sending each of them on calls
+// and counting the calls that overlapped the one of another host
+type testNotifyConn struct {
+	calls    chan string
+	inFlight int32
+	overlaps int32
+}
+
+func (c *testNotifyConn) Call(serviceMethod string, args interface{}, reply interface{}) error {
+	if atomic.AddInt32(&c.inFlight, 1) > 1 {
+		atomic.AddInt32(&c.overlaps, 1)
+	}
+	time.Sleep(time.Millisecond)
+	if rply, canCast := reply.(*string); canCast && rply != nil {
+		*rply = utils.OK
+	}
+	atomic.AddInt32(&c.inFlight, -1)
+	c.calls <- serviceMethod
+	return nil
+}
+
+func testPriorityTiersProfile(strategy string) *engine.DispatcherProfile {
+	return &engine.DispatcherProfile{
+		Tenant:   "cgrates.org",
+		ID:       "DSP_TIERS",
+		Strategy: strategy,
+		Hosts: engine.DispatcherHostProfiles{
+			{ID: "DSP_1", Weight: 20, Params: map[string]interface{}{utils.MetaPriority: 1}},
+			{ID: "DSP_2", Weight: 10, Params: map[string]interface{}{utils.MetaPriority: 1}},
+			{ID: "DSP_3", Weight: 10, Params: map[string]interface{}{utils.MetaPriority: 2}},
+			{ID: "DSP_4", Weight: 30},
+			{ID: "DSP_5", Weight: 20},
+			{ID: "DSP_6", Weight: 10},
+		},
+	}
+}
+
+func TestLibDispatcherPriorityTiersWeight(t *testing.T) {
+	d, err := newDispatcher(nil, testPriorityTiersProfile(utils.MetaWeight))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the failover goes through the whole tier 0 before reaching tier 1 and 2
+	exp := []string{"DSP_4", "DSP_5", "DSP_6", "DSP_1", "DSP_2", "DSP_3"}
+	for i := 0; i < 3; i++ {
+		if rply := d.HostIDs(); !reflect.DeepEqual(exp, rply) {
+			t.Errorf("expecting: %+v, received: %+v", exp, rply)
+		}
+	}
+}
+
+func TestLibDispatcherPriorityTiersRoundRobin(t *testing.T) {
+	d, err := newDispatcher(nil, testPriorityTiersProfile(utils.MetaRoundRobin))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, exp := range [][]string{
+		{"DSP_4", "DSP_5", "DSP_6", "DSP_1", "DSP_2", "DSP_3"},
+		{"DSP_5", "DSP_6", "DSP_4", "DSP_2", "DSP_1", "DSP_3"},
+		{"DSP_6", "DSP_4", "DSP_5", "DSP_1", "DSP_2", "DSP_3"},
+		{"DSP_4", "DSP_5", "DSP_6", "DSP_2", "DSP_1", "DSP_3"},
+		{"DSP_5", "DSP_6", "DSP_4", "DSP_1", "DSP_2", "DSP_3"},
+		{"DSP_6", "DSP_4", "DSP_5", "DSP_2", "DSP_1", "DSP_3"},
+		{"DSP_4", "DSP_5", "DSP_6", "DSP_1", "DSP_2", "DSP_3"},
+	} {
+		if rply := d.HostIDs(); !reflect.DeepEqual(exp, rply) {
+			t.Errorf("expecting: %+v, received: %+v", exp, rply)
+		}
+	}
+}
+
+func TestLibDispatcherPriorityTiersRandom(t *testing.T) {
+	d, err := newDispatcher(nil, testPriorityTiersProfile(utils.MetaRandom))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tier0 := utils.NewStringSet([]string{"DSP_4", "DSP_5", "DSP_6"})
+	tier1 := utils.NewStringSet([]string{"DSP_1", "DSP_2"})
+	for i := 0; i < 20; i++ {
+		rply := d.HostIDs()
+		for _, hostID := range rply[:3] {
+			if !tier0.Has(hostID) {
+				t.Fatalf("expecting tier 0 hosts first, received: %+v", rply)
+			}
+		}
+		for _, hostID := range rply[3:5] {
+			if !tier1.Has(hostID) {
+				t.Fatalf("expecting tier 1 hosts after tier 0, received: %+v", rply)
+			}
+		}
+		if rply[5] != "DSP_3" {
+			t.Fatalf("expecting tier 2 host last, received: %+v", rply)
+		}
+	}
+}
+
+// checkPriorityTiers fails the test if the hosts of testPriorityTiersProfile are not ordered per tier
+func checkPriorityTiers(t *testing.T, hostIDs []string) {
+	t.Helper()
+	for i, tier := range []utils.StringSet{
+		utils.NewStringSet([]string{"DSP_4", "DSP_5", "DSP_6"}),
+		utils.NewStringSet([]string{"DSP_1", "DSP_2"}),
+		utils.NewStringSet([]string{"DSP_3"}),
+	} {
+		if len(hostIDs) < tier.Size() {
+			t.Fatalf("expecting the hosts of tier %d, received: %+v", i, hostIDs)
+		}
+		for _, hostID := range hostIDs[:tier.Size()] {
+			if !tier.Has(hostID) {
+				t.Fatalf("expecting the hosts of tier %d, received: %+v", i, hostIDs)
+			}
+		}
+		hostIDs = hostIDs[tier.Size():]
+	}
+}
+
+func TestLibDispatcherPriorityTiersRatio(t *testing.T) {
+	d, err := newDispatcher(nil, testPriorityTiersProfile(utils.MetaRatio))
+	if err != nil {
+		t.Fatal(err)
+	}
+	firsts := make(map[string]int)
+	for i := 0; i < 6; i++ {
+		rply := d.HostIDs()
+		checkPriorityTiers(t, rply)
+		firsts[rply[0]]++
+	}
+	if exp := map[string]int{"DSP_4": 2, "DSP_5": 2, "DSP_6": 2}; !reflect.DeepEqual(exp, firsts) {
+		t.Errorf("expecting: %+v, received: %+v", exp, firsts)
+	}
+}
+
+func TestLibDispatcherPriorityTiersLoad(t *testing.T) {
+	pfl := testPriorityTiersProfile(utils.MetaLoad)
+	pfl.StrategyParams = map[string]interface{}{utils.MetaTieBreak: utils.MetaRoundRobin}
+	d, err := newDispatcher(nil, pfl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ld := d.(*WeightDispatcher).strategy.(*loadStrategyDispatcher)
+	lM, err := newLoadMetrics(pfl.Hosts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a loaded host of tier 0 goes last inside its tier only
+	lM.HostsLoad["DSP_4"] = 1
+	for i := 0; i < 4; i++ {
+		rply := ld.hostIDs(lM, d.HostIDs())
+		checkPriorityTiers(t, rply)
+		if rply[2] != "DSP_4" {
+			t.Errorf("expecting the loaded host last in tier 0, received: %+v", rply)
+		}
+	}
+	// even with the whole tier 0 loaded the higher tiers come after it
+	lM.HostsLoad["DSP_5"], lM.HostsLoad["DSP_6"] = 1, 1
+	for i := 0; i < 4; i++ {
+		checkPriorityTiers(t, ld.hostIDs(lM, d.HostIDs()))
+	}
+}
+
+func TestLibDispatcherHostsNotShared(t *testing.T) {
+	for _, strategy := range []string{utils.MetaWeight, utils.MetaRandom,
+		utils.MetaRoundRobin, utils.MetaBroadcast} {
+		pfl := &engine.DispatcherProfile{
+			Tenant:   "cgrates.org",
+			ID:       "DSP_CLONE",
+			Strategy: strategy,
+			Hosts: engine.DispatcherHostProfiles{
+				{ID: "DSP_1", Weight: 20, Params: map[string]interface{}{"region": "eu"}},
+				{ID: "DSP_2", Weight: 10, Params: map[string]interface{}{"region": "eu"}},
+			},
+		}
+		d, err := newDispatcher(nil, pfl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// changing the profile after construction or SetProfile should not reach the dispatcher
+		pfl.Hosts[0].Params["region"] = "us"
+		pfl.Hosts[1].ID = "DSP_3"
+		exp := utils.NewStringSet([]string{"DSP_1", "DSP_2"})
+		if rply := utils.NewStringSet(HostIDsMatching(d, map[string]string{"region": "eu"})); !reflect.DeepEqual(exp, rply) {
+			t.Errorf("for %s expecting: %+v, received: %+v", strategy, exp, rply)
+		}
+		d.SetProfile(pfl)
+		pfl.Hosts[0].Params["region"] = "eu"
+		pfl.Hosts[1].Params["region"] = "us"
+		exp = utils.NewStringSet([]string{"DSP_3"})
+		if rply := utils.NewStringSet(HostIDsMatching(d, map[string]string{"region": "eu"})); !reflect.DeepEqual(exp, rply) {
+			t.Errorf("for %s expecting: %+v, received: %+v", strategy, exp, rply)
+		}
+	}
+}
+
+func TestLibDispatcherHostIDsExcept(t *testing.T) {
+	pfl := &engine.DispatcherProfile{
+		Tenant:   "cgrates.org",
+		ID:       "DSP_EXCEPT",
+		Strategy: utils.MetaWeight,
+		Hosts: engine.DispatcherHostProfiles{
+			{ID: "DSP_1", Weight: 30},
+			{ID: "DSP_2", Weight: 20},
+			{ID: "DSP_3", Weight: 10},
+		},
+	}
+	d, err := newDispatcher(nil, pfl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		exclude utils.StringSet
+		exp     []string
+	}{
+		{nil, []string{"DSP_1", "DSP_2", "DSP_3"}},
+		{utils.NewStringSet([]string{"DSP_1"}), []string{"DSP_2", "DSP_3"}},
+		{utils.NewStringSet([]string{"DSP_2", "DSP_4"}), []string{"DSP_1", "DSP_3"}},
+		{utils.NewStringSet([]string{"DSP_1", "DSP_2", "DSP_3"}), []string{}},
+	} {
+		if rply := HostIDsExcept(d, tc.exclude); !reflect.DeepEqual(tc.exp, rply) {
+			t.Errorf("excluding %+v expecting: %+v, received: %+v", tc.exclude, tc.exp, rply)
+		}
+	}
+}
+
+func TestLibDispatcherHostIDsExceptRoundRobin(t *testing.T) {
+	pfl := &engine.DispatcherProfile{
+		Tenant:   "cgrates.org",
+		ID:       "DSP_EXCEPT",
+		Strategy: utils.MetaRoundRobin,
+		Hosts: engine.DispatcherHostProfiles{
+			{ID: "DSP_1", Weight: 30},
+			{ID: "DSP_2", Weight: 20},
+			{ID: "DSP_3", Weight: 10},
+		},
+	}
+	d, err := newDispatcher(nil, pfl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exclude := utils.NewStringSet([]string{"DSP_2"})
+	for _, exp := range [][]string{
+		{"DSP_1", "DSP_3"},
+		{"DSP_3", "DSP_1"},
+		{"DSP_3", "DSP_1"},
+		{"DSP_1", "DSP_3"},
+	} {
+		if rply := HostIDsExcept(d, exclude); !reflect.DeepEqual(exp, rply) {
+			t.Errorf("expecting: %+v, received: %+v", exp, rply)
+		}
+	}
+	if rply := HostIDsExcept(d, utils.NewStringSet([]string{"DSP_1", "DSP_2", "DSP_3"})); len(rply) != 0 {
+		t.Errorf("expecting no hosts, received: %+v", rply)
+	}
+}
+
+func TestLibDispatcherDegradations(t *testing.T) {
+	d, err := newDispatcher(nil, &engine.DispatcherProfile{
+		Tenant:         "cgrates.org",
+		ID:             "DSP_DEGRADED",
+		Strategy:       utils.MetaRandom,
+		StrategyParams: map[string]interface{}{utils.MetaEventIDSeed: true},
+		Hosts: engine.DispatcherHostProfiles{
+			{ID: "DSP_1"},
+			{ID: "DSP_2"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dgs := d.Degradations(); len(dgs) != 0 {
+		t.Errorf("expecting no degradations, received: %+v", dgs)
+	}
+	rd := d.(*RandomDispatcher)
+	rd.hostIDsForEvent(&utils.CGREvent{ID: "EV_1"})
+	rd.hostIDsForEvent(&utils.CGREvent{})
+	rd.hostIDsForEvent(nil)
+	if dgs := d.Degradations(); !reflect.DeepEqual(map[string]int64{DegradedMissingEventID: 2}, dgs) {
+		t.Errorf("expecting: %+v, received: %+v", map[string]int64{DegradedMissingEventID: 2}, dgs)
+	}
+
+	if d, err = newDispatcher(nil, &engine.DispatcherProfile{
+		Tenant:         "cgrates.org",
+		ID:             "DSP_DEGRADED",
+		Strategy:       utils.MetaBroadcast,
+		StrategyParams: map[string]interface{}{utils.MetaQuorum: 3},
+		Hosts: engine.DispatcherHostProfiles{
+			{ID: "DSP_1"},
+			{ID: "DSP_2"},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	var reply string
+	d.Dispatch(nil, nil, utils.MetaAttributes, utils.AttributeSv1Ping, new(utils.CGREvent), &reply)
+	if dgs := d.Degradations(); !reflect.DeepEqual(map[string]int64{DegradedQuorumOverHosts: 1}, dgs) {
+		t.Errorf("expecting: %+v, received: %+v", map[string]int64{DegradedQuorumOverHosts: 1}, dgs)
+	}
+
+	hosts := engine.DispatcherHostProfiles{
+		{ID: "DSP_1", Params: map[string]interface{}{utils.MetaRatio: 1}},
+		{ID: "DSP_2", Params: map[string]interface{}{utils.MetaRatio: 2}},
+	}
+	if d, err = newDispatcher(nil, &engine.DispatcherProfile{
+		Tenant:   "cgrates.org",
+		ID:       "DSP_DEGRADED",
+		Strategy: utils.MetaLoad,
+		Hosts:    hosts,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	ld := d.(*WeightDispatcher).strategy.(*loadStrategyDispatcher)
+	lM, err := newLoadMetrics(hosts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lM.HostsLoad["DSP_1"] = 1
+	ld.hostIDs(lM, []string{"DSP_1", "DSP_2"})
+	if dgs := d.Degradations(); len(dgs) != 0 {
+		t.Errorf("expecting no degradations, received: %+v", dgs)
+	}
+	lM.HostsLoad["DSP_2"] = 2
+	ld.hostIDs(lM, []string{"DSP_1", "DSP_2"})
+	if dgs := d.Degradations(); !reflect.DeepEqual(map[string]int64{DegradedAllHostsLoaded: 1}, dgs) {
+		t.Errorf("expecting: %+v, received: %+v", map[string]int64{DegradedAllHostsLoaded: 1}, dgs)
+	}
+}
+
+func TestLibDispatcherHostIDsHint(t *testing.T) {
+	d, err := newDispatcher(nil, &engine.DispatcherProfile{
+		Tenant:   "cgrates.org",
+		ID:       "DSP_HINT",
+		Strategy: utils.MetaWeight,
+		Hosts: engine.DispatcherHostProfiles{
+			{ID: "DSP_1", Weight: 30},
+			{ID: "DSP_2", Weight: 20},
+			{ID: "DSP_3", Weight: 10},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := []string{"DSP_3", "DSP_1", "DSP_2"}
+	if rply, used := HostIDsHint(d, "DSP_3"); !used {
+		t.Error("expecting the hint to be used")
+	} else if !reflect.DeepEqual(exp, rply) {
+		t.Errorf("expecting: %+v, received: %+v", exp, rply)
+	}
+	exp = []string{"DSP_1", "DSP_2", "DSP_3"}
+	if rply, used := HostIDsHint(d, "DSP_1"); !used {
+		t.Error("expecting the hint to be used")
+	} else if !reflect.DeepEqual(exp, rply) {
+		t.Errorf("expecting: %+v, received: %+v", exp, rply)
+	}
+	if rply, used := HostIDsHint(d, "DSP_4"); used {
+		t.Error("not expecting an unknown hint to be used")
+	} else if !reflect.DeepEqual(exp, rply) {
+		t.Errorf("expecting: %+v, received: %+v", exp, rply)
+	}
+	if rply, used := HostIDsHint(d, utils.EmptyString); used {
+		t.Error("not expecting an empty hint to be used")
+	} else if !reflect.DeepEqual(exp, rply) {
+		t.Errorf("expecting: %+v, received: %+v", exp, rply)
+	}
+}
+
+func TestLibDispatcherHostIDsHintRoundRobin(t *testing.T) {
+	d, err := newDispatcher(nil, &engine.DispatcherProfile{
+		Tenant:   "cgrates.org",
+		ID:       "DSP_HINT",
+		Strategy: utils.MetaRoundRobin,
+		Hosts: engine.DispatcherHostProfiles{
+			{ID: "DSP_1", Weight: 30},
+			{ID: "DSP_2", Weight: 20},
+			{ID: "DSP_3", Weight: 10},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the hint does not stop the rotation for the rest of the hosts
+	for _, exp := range [][]string{
+		{"DSP_2", "DSP_1", "DSP_3"},
+		{"DSP_2", "DSP_3", "DSP_1"},
+		{"DSP_2", "DSP_3", "DSP_1"},
+	} {
+		if rply, used := HostIDsHint(d, "DSP_2"); !used {
+			t.Error("expecting the hint to be used")
+		} else if !reflect.DeepEqual(exp, rply) {
+			t.Errorf("expecting: %+v, received: %+v", exp, rply)
+		}
+	}
+}
+
+func TestLibDispatcherHostIDsPreferring(t *testing.T) {
+	d, err := newDispatcher(nil, &engine.DispatcherProfile{
+		Tenant:   "cgrates.org",
+		ID:       "DSP_PREFER",
+		Strategy: utils.MetaWeight,
+		Hosts: engine.DispatcherHostProfiles{
+			{ID: "DSP_1", Weight: 40},
+			{ID: "DSP_2", Weight: 30},
+			{ID: "DSP_3", Weight: 20},
+			{ID: "DSP_4", Weight: 10},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := []string{"DSP_3", "DSP_2", "DSP_1", "DSP_4"}
+	if rply, used := HostIDsPreferring(d, []string{"DSP_3", "DSP_2"}); !used {
+		t.Error("expecting the preferred hosts to be used")
+	} else if !reflect.DeepEqual(exp, rply) {
+		t.Errorf("expecting: %+v, received: %+v", exp, rply)
+	}
+	// the unknown and repeated preferences are skipped
+	exp = []string{"DSP_4", "DSP_1", "DSP_2", "DSP_3"}
+	if rply, used := HostIDsPreferring(d, []string{"DSP_5", "DSP_4", "DSP_4", utils.EmptyString}); !used {
+		t.Error("expecting the preferred hosts to be used")
+	} else if !reflect.DeepEqual(exp, rply) {
+		t.Errorf("expecting: %+v, received: %+v", exp, rply)
+	}
+	exp = []string{"DSP_1", "DSP_2", "DSP_3", "DSP_4"}
+	for _, preferred := range [][]string{nil, {}, {"DSP_5", "DSP_6"}} {
+		if rply, used := HostIDsPreferring(d, preferred); used {
+			t.Errorf("not expecting %q to be used", preferred)
+		} else if !reflect.DeepEqual(exp, rply) {
+			t.Errorf("expecting: %+v, received: %+v", exp, rply)
+		}
+	}
+	// the strategy still applies to the hosts after the preferred ones
+	if d, err = newDispatcher(nil, testPriorityTiersProfile(utils.MetaRoundRobin)); err != nil {
+		t.Fatal(err)
+	}
+	for _, exp := range [][]string{
+		{"DSP_3", "DSP_6", "DSP_4", "DSP_5", "DSP_1", "DSP_2"},
+		{"DSP_3", "DSP_6", "DSP_5", "DSP_4", "DSP_2", "DSP_1"},
+	} {
+		if rply, used := HostIDsPreferring(d, []string{"DSP_3", "DSP_6"}); !used {
+			t.Error("expecting the preferred hosts to be used")
+		} else if !reflect.DeepEqual(exp, rply) {
+			t.Errorf("expecting: %+v, received: %+v", exp, rply)
+		}
+	}
+}
+
+func testTieBreakProfile(tieBreak string) *engine.DispatcherProfile {
+	return &engine.DispatcherProfile{
+		Tenant:         "cgrates.org",
+		ID:             "DSP_TIE_BREAK",
+		Strategy:       utils.MetaWeight,
+		StrategyParams: map[string]interface{}{utils.MetaTieBreak: tieBreak},
+		Hosts: engine.DispatcherHostProfiles{
+			{ID: "DSP_C", Weight: 10},
+			{ID: "DSP_B", Weight: 10},
+			{ID: "DSP_D", Weight: 20},
+			{ID: "DSP_A", Weight: 10},
+		},
+	}
+}
+
+func TestLibDispatcherTieBreakID(t *testing.T) {
+	d, err := newDispatcher(nil, testTieBreakProfile(utils.MetaID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := []string{"DSP_D", "DSP_A", "DSP_B", "DSP_C"}
+	for i := 0; i < 5; i++ {
+		if rply := d.HostIDs(); !reflect.DeepEqual(exp, rply) {
+			t.Errorf("expecting: %+v, received: %+v", exp, rply)
+		}
+	}
+}
+
+func TestLibDispatcherTieBreakRoundRobin(t *testing.T) {
+	d, err := newDispatcher(nil, testTieBreakProfile(utils.MetaRoundRobin))
+	if err != nil {
+		t.Fatal(err)
+	}
+	firsts := make(map[string]int)
+	for i := 0; i < 30; i++ {
+		rply := d.HostIDs()
+		if rply[0] != "DSP_D" {
+			t.Fatalf("expecting the heaviest host first, received: %+v", rply)
+		}
+		firsts[rply[1]]++
+	}
+	// every tied host leads the tie the same number of times
+	for hostID, count := range firsts {
+		if count != 10 {
+			t.Errorf("expecting %s to lead the tie 10 times, received: %d", hostID, count)
+		}
+	}
+	if len(firsts) != 3 {
+		t.Errorf("expecting all tied hosts to lead, received: %+v", firsts)
+	}
+}
+
+func TestLibDispatcherTieBreakRandom(t *testing.T) {
+	d, err := newDispatcher(nil, testTieBreakProfile(utils.MetaRandom))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := checkDistribution(func() string {
+		rply := d.HostIDs()
+		if rply[0] != "DSP_D" {
+			t.Fatalf("expecting the heaviest host first, received: %+v", rply)
+		}
+		return rply[1]
+	}, 3000, map[string]float64{"DSP_A": 1. / 3, "DSP_B": 1. / 3, "DSP_C": 1. / 3}, 0.05); err != nil {
+		t.Errorf("uneven tie break: %v", err)
+	}
+}
+
+func TestLibDispatcherTieBreakLoad(t *testing.T) {
+	pfl := testTieBreakProfile(utils.MetaRoundRobin)
+	pfl.Strategy = utils.MetaLoad
+	d, err := newDispatcher(nil, pfl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ld := d.(*WeightDispatcher).strategy.(*loadStrategyDispatcher)
+	lM, err := newLoadMetrics(pfl.Hosts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// DSP_D is the most loaded so the other three are tied at the lowest cost
+	lM.HostsLoad["DSP_D"] = 1
+	firsts := make(map[string]int)
+	for i := 0; i < 30; i++ {
+		rply := ld.hostIDs(lM, d.HostIDs())
+		if rply[3] != "DSP_D" {
+			t.Fatalf("expecting the loaded host last, received: %+v", rply)
+		}
+		firsts[rply[0]]++
+	}
+	for _, hostID := range []string{"DSP_A", "DSP_B", "DSP_C"} {
+		if firsts[hostID] != 10 {
+			t.Errorf("expecting %s to lead the tie 10 times, received: %d", hostID, firsts[hostID])
+		}
+	}
+	if _, err := newDispatcher(nil, testTieBreakProfile("*unknown")); err == nil {
+		t.Error("expecting error for unsupported tie break")
+	}
+}
+
+func TestLibDispatcherSetProfileLoad(t *testing.T) {
+	pfl := testTieBreakProfile(utils.MetaID)
+	pfl.Strategy = utils.MetaLoad
+	d, err := newDispatcher(nil, pfl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ld := d.(*WeightDispatcher).strategy.(*loadStrategyDispatcher)
+	pfl = testTieBreakProfile(utils.MetaRoundRobin)
+	pfl.Strategy = utils.MetaLoad
+	pfl.Hosts = append(pfl.Hosts, &engine.DispatcherHostProfile{ID: "DSP_E", Weight: 5})
+	d.SetProfile(pfl)
+	if len(ld.hosts) != 5 {
+		t.Errorf("expecting 5 *load hosts, received: %+v", ld.hosts)
+	}
+	if policy := ld.tieBreak.policy(); policy != utils.MetaRoundRobin {
+		t.Errorf("expecting: %s, received: %s", utils.MetaRoundRobin, policy)
+	}
+	if err := d.Validate(); err != nil {
+		t.Error(err)
+	}
+	// an invalid tie break keeps the previous one but still refreshes the hosts
+	pfl = testTieBreakProfile("*unknown")
+	pfl.Strategy = utils.MetaLoad
+	d.SetProfile(pfl)
+	if len(ld.hosts) != 4 {
+		t.Errorf("expecting 4 *load hosts, received: %+v", ld.hosts)
+	}
+	if policy := ld.tieBreak.policy(); policy != utils.MetaRoundRobin {
+		t.Errorf("expecting: %s, received: %s", utils.MetaRoundRobin, policy)
+	}
+}
+
+func TestLibDispatcherLoadMetricsGetHosts(t *testing.T) {
+	lM := &LoadMetrics{
+		HostsLoad:  map[string]int64{"DSP_1": 3, "DSP_2": 0, "DSP_3": 1},
+		HostsRatio: map[string]int64{"DSP_1": 5, "DSP_2": 1, "DSP_3": 1},
+		SumRatio:   7,
+	}
+	exp := []string{"DSP_2", "DSP_1", "DSP_3"}
+	if rply := lM.getHosts([]string{"DSP_1", "DSP_2", "DSP_3"}, nil); !reflect.DeepEqual(exp, rply) {
+		t.Errorf("expecting: %+v, received: %+v", exp, rply)
+	}
+	// the priority tier comes before the cost
+	exp = []string{"DSP_1", "DSP_3", "DSP_2"}
+	if rply := lM.getHosts([]string{"DSP_1", "DSP_2", "DSP_3"},
+		map[string]int{"DSP_2": 1}); !reflect.DeepEqual(exp, rply) {
+		t.Errorf("expecting: %+v, received: %+v", exp, rply)
+	}
+}
+
+func TestLibDispatcherProfile(t *testing.T) {
+	pfl := &engine.DispatcherProfile{
+		Tenant:   "cgrates.org",
+		ID:       "DSP_PROFILE",
+		Strategy: utils.MetaRoundRobin,
+		Hosts: engine.DispatcherHostProfiles{
+			{ID: "DSP_1", Weight: 10},
+			{ID: "DSP_2", Weight: 20},
+		},
+	}
+	d, err := newDispatcher(nil, pfl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rcv := d.Profile(); !reflect.DeepEqual(pfl, rcv) {
+		t.Errorf("expecting: %+v, received: %+v", utils.ToJSON(pfl), utils.ToJSON(rcv))
+	}
+	pfl2 := &engine.DispatcherProfile{
+		Tenant:   "cgrates.org",
+		ID:       "DSP_PROFILE",
+		Strategy: utils.MetaRoundRobin,
+		Hosts: engine.DispatcherHostProfiles{
+			{ID: "DSP_3", Weight: 10},
+		},
+	}
+	d.SetProfile(pfl2)
+	rcv := d.Profile()
+	if !reflect.DeepEqual(pfl2, rcv) {
+		t.Errorf("expecting: %+v, received: %+v", utils.ToJSON(pfl2), utils.ToJSON(rcv))
+	}
+	rcv.Hosts[0].ID = "DSP_4"
+	pfl2.Hosts[0].ID = "DSP_5"
+	exp := []string{"DSP_3"}
+	if rcv := d.Profile().Hosts.HostIDs(); !reflect.DeepEqual(exp, rcv) {
+		t.Errorf("expecting: %+v, received: %+v", exp, rcv)
+	}
+}
+
+func TestLibDispatcherSelectN(t *testing.T) {
+	d, err := newDispatcher(nil, &engine.DispatcherProfile{
+		Tenant:   "cgrates.org",
+		ID:       "DSP_SELECTN",
+		Strategy: utils.MetaWeight,
+		Hosts: engine.DispatcherHostProfiles{
+			{ID: "DSP_1", Weight: 30},
+			{ID: "DSP_2", Weight: 20},
+			{ID: "DSP_3", Weight: 10},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for n, exp := range map[int][]string{
+		-1: {},
+		0:  {},
+		2:  {"DSP_1", "DSP_2"},
+		3:  {"DSP_1", "DSP_2", "DSP_3"},
+		5:  {"DSP_1", "DSP_2", "DSP_3"},
+	} {
+		if rply := SelectN(d, n); !reflect.DeepEqual(exp, rply) {
+			t.Errorf("for %d expecting: %+v, received: %+v", n, exp, rply)
+		}
+	}
+	// exclusions reduce the available hosts
+	exp := []string{"DSP_2"}
+	if rply := firstHostIDs(HostIDsExcept(d, utils.NewStringSet([]string{"DSP_1", "DSP_3"})), 2); !reflect.DeepEqual(exp, rply) {
+		t.Errorf("expecting: %+v, received: %+v", exp, rply)
+	}
+}
+
+func TestLibDispatcherSelectNRandom(t *testing.T) {
+	d, err := newDispatcher(nil, &engine.DispatcherProfile{
+		Tenant:   "cgrates.org",
+		ID:       "DSP_SELECTN",
+		Strategy: utils.MetaRandom,
+		Hosts: engine.DispatcherHostProfiles{
+			{ID: "DSP_1"},
+			{ID: "DSP_2"},
+			{ID: "DSP_3"},
+			{ID: "DSP_4"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 20; i++ {
+		rply := SelectN(d, 3)
+		if len(rply) != 3 {
+			t.Fatalf("expecting 3 hosts, received: %+v", rply)
+		}
+		if len(utils.NewStringSet(rply)) != 3 {
+			t.Fatalf("expecting distinct hosts, received: %+v", rply)
+		}
+	}
+}
+
+func TestLibDispatcherProfileHash(t *testing.T) {
+	newPfl := func() *engine.DispatcherProfile {
+		return &engine.DispatcherProfile{
+			Tenant:         "cgrates.org",
+			ID:             "DSP_HASH",
+			Strategy:       utils.MetaWeight,
+			StrategyParams: map[string]interface{}{utils.MetaTieBreak: utils.MetaID},
+			Hosts: engine.DispatcherHostProfiles{
+				{ID: "DSP_1", Weight: 20, Params: map[string]interface{}{utils.MetaRatio: 2}},
+				{ID: "DSP_2", Weight: 10, Blocker: true},
+			},
+		}
+	}
+	d, err := newDispatcher(nil, newPfl())
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash := d.ProfileHash()
+	if hash == utils.EmptyString {
+		t.Fatal("expecting a profile hash")
+	}
+	pfl := newPfl()
+	pfl.Hosts[0], pfl.Hosts[1] = pfl.Hosts[1], pfl.Hosts[0]
+	pfl.Weight = 30 // not used by the dispatcher
+	d.SetProfile(pfl)
+	if rply := d.ProfileHash(); rply != hash {
+		t.Errorf("expecting hash: %q, received: %q", hash, rply)
+	}
+	for _, change := range []func(*engine.DispatcherProfile){
+		func(pfl *engine.DispatcherProfile) { pfl.Strategy = utils.MetaRoundRobin },
+		func(pfl *engine.DispatcherProfile) { pfl.StrategyParams[utils.MetaTieBreak] = utils.MetaRandom },
+		func(pfl *engine.DispatcherProfile) { pfl.Hosts[1].Weight = 30 },
+		func(pfl *engine.DispatcherProfile) { pfl.Hosts[1].Blocker = false },
+		func(pfl *engine.DispatcherProfile) { pfl.Hosts[0].Params[utils.MetaRatio] = 3 },
+		func(pfl *engine.DispatcherProfile) { pfl.Hosts = pfl.Hosts[:1] },
+	} {
+		pfl := newPfl()
+		change(pfl)
+		if rply := profileHash(pfl); rply == hash {
+			t.Errorf("expecting a different hash for: %s", utils.ToJSON(pfl))
+		}
+	}
+}
+
+func TestLibDispatcherAntiAffinity(t *testing.T) {
+	runs := 10000
+	repeats := func(params map[string]interface{}) (rpts int, shares map[string]int) {
+		d, err := newDispatcher(nil, &engine.DispatcherProfile{
+			Tenant:         "cgrates.org",
+			ID:             "DSP_ANTI_AFFINITY",
+			Strategy:       utils.MetaRandom,
+			StrategyParams: params,
+			Hosts: engine.DispatcherHostProfiles{
+				{ID: "DSP_1"},
+				{ID: "DSP_2"},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		shares = make(map[string]int)
+		var last string
+		for i := 0; i < runs; i++ {
+			first := d.HostIDs()[0]
+			if first == last {
+				rpts++
+			}
+			shares[first]++
+			last = first
+		}
+		return
+	}
+	off, offShares := repeats(nil)
+	on, onShares := repeats(map[string]interface{}{utils.MetaAntiAffinity: true})
+	// repeats drop from about half to about a quarter of the runs
+	if on >= off*3/4 {
+		t.Errorf("expecting less repeats with %s, received: %d, without: %d", utils.MetaAntiAffinity, on, off)
+	}
+	if on == 0 {
+		t.Errorf("expecting repeats to be discouraged, not forbidden")
+	}
+	for _, shares := range []map[string]int{offShares, onShares} {
+		for hostID, share := range shares {
+			if share < runs*45/100 || share > runs*55/100 {
+				t.Errorf("expecting an even distribution, received %d for %s", share, hostID)
+			}
+		}
+	}
+	if _, err := newDispatcher(nil, &engine.DispatcherProfile{
+		Strategy:       utils.MetaRandom,
+		StrategyParams: map[string]interface{}{utils.MetaAntiAffinity: "maybe"},
+	}); err == nil {
+		t.Error("expecting error for invalid " + utils.MetaAntiAffinity)
+	}
+}
+
+// checkDistribution calls selectHost for the given number of runs and returns an error
+// listing the actual versus expected shares if any of them is outside the tolerance
+func checkDistribution(selectHost func() string, runs int,
+	exp map[string]float64, tolerance float64) error {
+	counts := make(map[string]int)
+	for i := 0; i < runs; i++ {
+		counts[selectHost()]++
+	}
+	hostIDs := make([]string, 0, len(exp)+len(counts))
+	for hostID := range exp {
+		hostIDs = append(hostIDs, hostID)
+	}
+	for hostID := range counts {
+		if _, has := exp[hostID]; !has {
+			hostIDs = append(hostIDs, hostID)
+		}
+	}
+	sort.Strings(hostIDs)
+	var failed bool
+	lines := make([]string, len(hostIDs))
+	for i, hostID := range hostIDs {
+		share := float64(counts[hostID]) / float64(runs)
+		mark := ""
+		if share < exp[hostID]-tolerance || share > exp[hostID]+tolerance {
+			failed = true
+			mark = " <-"
+		}
+		lines[i] = fmt.Sprintf("%s: expected %.3f±%.3f, actual %.3f%s",
+			hostID, exp[hostID], tolerance, share, mark)
+	}
+	if !failed {
+		return nil
+	}
+	return fmt.Errorf("distribution over %d runs out of tolerance:\n%s", runs, strings.Join(lines, "\n"))
+}
+
+func TestLibDispatcherCheckDistribution(t *testing.T) {
+	hostIDs := []string{"DSP_1", "DSP_1", "DSP_1", "DSP_2"}
+	var i int
+	selectHost := func() (hostID string) {
+		hostID = hostIDs[i%len(hostIDs)]
+		i++
+		return
+	}
+	if err := checkDistribution(selectHost, 400,
+		map[string]float64{"DSP_1": 0.75, "DSP_2": 0.25}, 0.01); err != nil {
+		t.Error(err)
+	}
+	err := checkDistribution(selectHost, 400,
+		map[string]float64{"DSP_1": 0.5, "DSP_3": 0.25}, 0.05)
+	if err == nil {
+		t.Fatal("expecting distribution error")
+	}
+	exp := "distribution over 400 runs out of tolerance:\n" +
+		"DSP_1: expected 0.500±0.050, actual 0.750 <-\n" +
+		"DSP_2: expected 0.000±0.050, actual 0.250 <-\n" +
+		"DSP_3: expected 0.250±0.050, actual 0.000 <-"
+	if err.Error() != exp {
+		t.Errorf("expecting:\n%s\nreceived:\n%s", exp, err.Error())
+	}
+}
+
+func TestLibDispatcherStrategyOverride(t *testing.T) {
+	d, err := newDispatcher(nil, &engine.DispatcherProfile{
+		Tenant:   "cgrates.org",
+		ID:       "DSP_OVERRIDE",
+		Strategy: utils.MetaRoundRobin,
+		Hosts: engine.DispatcherHostProfiles{
+			{ID: "DSP_1", Weight: 30},
+			{ID: "DSP_2", Weight: 20},
+			{ID: "DSP_3", Weight: 10},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr := d.(*RoundRobinDispatcher)
+	exp := []string{"DSP_1", "DSP_2", "DSP_3"}
+	for i := 0; i < 5; i++ {
+		if rply, overridden := rr.hostIDsOverride(utils.StringPointer(utils.MetaWeight),
+			rr.strategyDegradations); !overridden {
+			t.Fatal("expecting the strategy to be overridden")
+		} else if !reflect.DeepEqual(exp, rply) {
+			t.Errorf("expecting: %+v, received: %+v", exp, rply)
+		}
+	}
+	if err := checkDistribution(func() string {
+		rply, _ := rr.hostIDsOverride(utils.StringPointer(utils.MetaRandom), rr.strategyDegradations)
+		return rply[0]
+	}, 3000, map[string]float64{"DSP_1": 1. / 3, "DSP_2": 1. / 3, "DSP_3": 1. / 3}, 0.05); err != nil {
+		t.Errorf("uneven random override: %v", err)
+	}
+	// the overrides did not move the round robin
+	if rply := d.HostIDs(); !reflect.DeepEqual(exp, rply) {
+		t.Errorf("expecting: %+v, received: %+v", exp, rply)
+	}
+	for _, strategy := range []*string{nil, utils.StringPointer(utils.EmptyString),
+		utils.StringPointer(utils.MetaRoundRobin), utils.StringPointer("*unknown")} {
+		if _, overridden := rr.hostIDsOverride(strategy, rr.strategyDegradations); overridden {
+			t.Errorf("not expecting %v to override the strategy", strategy)
+		}
+	}
+	// only the unsupported override is counted, without logging on every call
+	if exp := map[string]int64{DegradedUnsupportedOverride: 1}; !reflect.DeepEqual(exp, d.Degradations()) {
+		t.Errorf("expecting: %+v, received: %+v", exp, d.Degradations())
+	}
+	// the override comes with the ArgDispatcher of the call
+	if err := d.Dispatch(nil, &utils.ArgDispatcher{Strategy: utils.StringPointer("*unknown")},
+		utils.MetaAttributes, utils.AttributeSv1Ping, &utils.CGREvent{}, nil); err == nil ||
+		!strings.Contains(err.Error(), utils.ErrNoDatabaseConn.Error()) {
+		t.Errorf("expecting: %v, received: %v", utils.ErrNoDatabaseConn, err)
+	}
+	if exp := map[string]int64{DegradedUnsupportedOverride: 2}; !reflect.DeepEqual(exp, d.Degradations()) {
+		t.Errorf("expecting: %+v, received: %+v", exp, d.Degradations())
+	}
+}
+
+func TestLibDispatcherDuplicatedHosts(t *testing.T) {
+	pfl := &engine.DispatcherProfile{
+		Tenant:   "cgrates.org",
+		ID:       "DSP_DUPLICATES",
+		Strategy: utils.MetaLoad,
+		Hosts: engine.DispatcherHostProfiles{
+			{ID: "DSP_1", Weight: 10, Params: map[string]interface{}{utils.MetaRatio: 1}},
+			{ID: "DSP_2", Weight: 20, Params: map[string]interface{}{utils.MetaRatio: 2}},
+			{ID: "DSP_1", Weight: 30, Params: map[string]interface{}{utils.MetaRatio: 3}},
+		},
+	}
+	uHosts, dupIDs := uniqueHosts(pfl.Hosts)
+	if exp := []string{"DSP_1"}; !reflect.DeepEqual(exp, dupIDs) {
+		t.Errorf("expecting duplicates: %+v, received: %+v", exp, dupIDs)
+	}
+	if exp := []string{"DSP_1", "DSP_2"}; !reflect.DeepEqual(exp, uHosts.HostIDs()) {
+		t.Errorf("expecting: %+v, received: %+v", exp, uHosts.HostIDs())
+	}
+	d, err := newDispatcher(nil, pfl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the heaviest occurrence is kept
+	if exp := []string{"DSP_1", "DSP_2"}; !reflect.DeepEqual(exp, d.HostIDs()) {
+		t.Errorf("expecting: %+v, received: %+v", exp, d.HostIDs())
+	}
+	lM, err := newLoadMetrics(d.(*WeightDispatcher).strategy.(*loadStrategyDispatcher).hosts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lM.SumRatio != 5 {
+		t.Errorf("expecting the ratio of DSP_1 counted once, received: %d", lM.SumRatio)
+	}
+	pfl.Strategy = utils.MetaRoundRobin
+	rr, err := newDispatcher(nil, pfl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pfl.Hosts = append(pfl.Hosts, &engine.DispatcherHostProfile{ID: "DSP_2", Weight: 40})
+	rr.SetProfile(pfl)
+	for _, exp := range [][]string{{"DSP_2", "DSP_1"}, {"DSP_1", "DSP_2"}, {"DSP_2", "DSP_1"}} {
+		if rply := rr.HostIDs(); !reflect.DeepEqual(exp, rply) {
+			t.Errorf("expecting: %+v, received: %+v", exp, rply)
+		}
+	}
+}
+
+func TestLibDispatcherRatio(t *testing.T) {
+	pfl := &engine.DispatcherProfile{
+		Tenant:   "cgrates.org",
+		ID:       "DSP_RATIO",
+		Strategy: utils.MetaRatio,
+		Hosts: engine.DispatcherHostProfiles{
+			{ID: "DSP_A", Weight: 30, Params: map[string]interface{}{utils.MetaRatio: 3}},
+			{ID: "DSP_B", Weight: 20, Params: map[string]interface{}{utils.MetaRatio: "2"}},
+			{ID: "DSP_C", Weight: 10},
+			{ID: "DSP_D", Params: map[string]interface{}{utils.MetaPriority: 1, utils.MetaRatio: 0}},
+		},
+	}
+	d, err := newDispatcher(nil, pfl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := [][]string{
+		{"DSP_A", "DSP_B", "DSP_C", "DSP_D"},
+		{"DSP_B", "DSP_A", "DSP_C", "DSP_D"},
+		{"DSP_A", "DSP_B", "DSP_C", "DSP_D"},
+		{"DSP_C", "DSP_A", "DSP_B", "DSP_D"},
+		{"DSP_B", "DSP_A", "DSP_C", "DSP_D"},
+		{"DSP_A", "DSP_B", "DSP_C", "DSP_D"},
+	}
+	for cycle := 0; cycle < 3; cycle++ {
+		counts := make(map[string]int)
+		for i := range exp {
+			rply := d.HostIDs()
+			if !reflect.DeepEqual(exp[i], rply) {
+				t.Errorf("cycle %d step %d expecting: %+v, received: %+v", cycle, i, exp[i], rply)
+			}
+			counts[rply[0]]++
+		}
+		if expCounts := map[string]int{"DSP_A": 3, "DSP_B": 2, "DSP_C": 1}; !reflect.DeepEqual(expCounts, counts) {
+			t.Errorf("expecting: %+v, received: %+v", expCounts, counts)
+		}
+	}
+	pfl.Hosts[0].Params[utils.MetaRatio] = -1
+	if _, err := newDispatcher(nil, pfl); err == nil {
+		t.Error("expecting error for negative ratio")
+	}
+	// invalid ratio on update keeps the previous hosts
+	d.SetProfile(pfl)
+	if rply := d.HostIDs(); !reflect.DeepEqual(exp[0], rply) {
+		t.Errorf("expecting: %+v, received: %+v", exp[0], rply)
+	}
+	pfl.Hosts[0].Params[utils.MetaRatio] = "three"
+	if _, err := newDispatcher(nil, pfl); err == nil {
+		t.Error("expecting error for invalid ratio")
+	}
+	pfl.Hosts[0].Params[utils.MetaRatio] = 1
+	d.SetProfile(pfl)
+	exp = [][]string{
+		{"DSP_B", "DSP_A", "DSP_C", "DSP_D"},
+		{"DSP_A", "DSP_B", "DSP_C", "DSP_D"},
+		{"DSP_C", "DSP_A", "DSP_B", "DSP_D"},
+		{"DSP_B", "DSP_A", "DSP_C", "DSP_D"},
+	}
+	for i := range exp {
+		if rply := d.HostIDs(); !reflect.DeepEqual(exp[i], rply) {
+			t.Errorf("step %d expecting: %+v, received: %+v", i, exp[i], rply)
+		}
+	}
+}
+
+// testRandomHosts returns between 1 and 10 sorted hosts spread over up to 3 priority tiers
+func testRandomHosts(rnd *rand.Rand) (hosts engine.DispatcherHostProfiles) {
+	hosts = make(engine.DispatcherHostProfiles, 1+rnd.Intn(10))
+	for i := range hosts {
+		hosts[i] = &engine.DispatcherHostProfile{
+			ID:     fmt.Sprintf("DSP_%d", i),
+			Weight: float64(rnd.Intn(3)),
+			Params: map[string]interface{}{utils.MetaPriority: rnd.Intn(3)},
+		}
+	}
+	hosts.Sort()
+	return
+}
+
+// checkTiersKept returns an error if hostIDs is not a permutation of the hosts keeping each ID in its tier
+func checkTiersKept(hosts engine.DispatcherHostProfiles, hostIDs []string) error {
+	if len(hostIDs) != len(hosts) {
+		return fmt.Errorf("expecting %d hosts, received: %+v", len(hosts), hostIDs)
+	}
+	var i int
+	for _, tier := range hosts.Tiers() {
+		if tierIDs := utils.NewStringSet(tier.HostIDs()); !reflect.DeepEqual(tierIDs, utils.NewStringSet(hostIDs[i:i+len(tier)])) {
+			return fmt.Errorf("expecting tier %+v, received: %+v", tier.HostIDs(), hostIDs[i:i+len(tier)])
+		}
+		i += len(tier)
+	}
+	return nil
+}
+
+func TestLibDispatcherRoundRobinHostIDsProperties(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for run := 0; run < 200; run++ {
+		hosts := testRandomHosts(rnd)
+		initIDs := hosts.HostIDs()
+		cycle := 1
+		for _, tier := range hosts.Tiers() {
+			cycle = lcm(cycle, len(tier))
+		}
+		leads := make(map[string]int)
+		var idx int
+		for i := 0; i < cycle; i++ {
+			hostIDs, nextIdx := roundRobinHostIDs(hosts, idx)
+			if err := checkTiersKept(hosts, hostIDs); err != nil {
+				t.Fatal(err)
+			}
+			if exp := (idx + 1) % cycle; nextIdx != exp {
+				t.Fatalf("expecting next index %d, received: %d", exp, nextIdx)
+			}
+			var start int
+			for _, tier := range hosts.Tiers() {
+				leads[hostIDs[start]]++
+				start += len(tier)
+			}
+			idx = nextIdx
+		}
+		if idx != 0 {
+			t.Fatalf("expecting the cycle to restart, received index: %d", idx)
+		}
+		// each host leads its tier the same number of times over a cycle
+		for _, tier := range hosts.Tiers() {
+			for _, hostID := range tier.HostIDs() {
+				if exp := cycle / len(tier); leads[hostID] != exp {
+					t.Fatalf("expecting %s to lead %d times, received: %d", hostID, exp, leads[hostID])
+				}
+			}
+		}
+		if !reflect.DeepEqual(initIDs, hosts.HostIDs()) {
+			t.Fatalf("expecting the hosts unchanged: %+v, received: %+v", initIDs, hosts.HostIDs())
+		}
+	}
+}
+
+func TestLibDispatcherWeightHostIDsProperties(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for run := 0; run < 200; run++ {
+		hosts := testRandomHosts(rnd)
+		initIDs := hosts.HostIDs()
+		byID := make(map[string]*engine.DispatcherHostProfile)
+		for _, host := range hosts {
+			byID[host.ID] = host
+		}
+		for _, tbPolicy := range []string{utils.EmptyString, utils.MetaID, utils.MetaRandom, utils.MetaRoundRobin} {
+			hostIDs := weightHostIDs(hosts, tbPolicy, rnd.Intn(10))
+			if err := checkTiersKept(hosts, hostIDs); err != nil {
+				t.Fatal(err)
+			}
+			for i := 1; i < len(hostIDs); i++ {
+				prev, crnt := byID[hostIDs[i-1]], byID[hostIDs[i]]
+				prevPrio, _ := prev.Priority()
+				crntPrio, _ := crnt.Priority()
+				if prevPrio == crntPrio && prev.Weight < crnt.Weight {
+					t.Fatalf("for %q expecting %s before %s: %+v", tbPolicy, crnt.ID, prev.ID, hostIDs)
+				}
+				if tbPolicy == utils.MetaID && prevPrio == crntPrio &&
+					prev.Weight == crnt.Weight && prev.ID > crnt.ID {
+					t.Fatalf("expecting tied hosts sorted by ID: %+v", hostIDs)
+				}
+			}
+			if tbPolicy == utils.EmptyString && !reflect.DeepEqual(initIDs, hostIDs) {
+				t.Fatalf("expecting the sorting order: %+v, received: %+v", initIDs, hostIDs)
+			}
+		}
+		if !reflect.DeepEqual(initIDs, hosts.HostIDs()) {
+			t.Fatalf("expecting the hosts unchanged: %+v, received: %+v", initIDs, hosts.HostIDs())
+		}
+	}
+}
+
+func TestLibDispatcherFailoverDepths(t *testing.T) {
+	d, err := newDispatcher(nil, &engine.DispatcherProfile{
+		Tenant:   "cgrates.org",
+		ID:       "DSP_DEPTHS",
+		Strategy: utils.MetaWeight,
+		Hosts: engine.DispatcherHostProfiles{
+			{ID: "DSP_1", Weight: 20},
+			{ID: "DSP_2", Weight: 10},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// failing to get the host is not an answered call
+	if err := d.Dispatch(nil, nil, utils.MetaAttributes, utils.AttributeSv1Ping,
+		nil, new(string)); err == nil {
+		t.Error("expecting error without DataManager")
+	}
+	if rply := d.FailoverDepthDistribution(); len(rply) != 0 {
+		t.Errorf("expecting no depths, received: %+v", rply)
+	}
+	conn := new(testDisconnectConn)
+	defer setTestInternalHosts(t, conn, "DSP_1", "DSP_2", "DSP_3")()
+	pfl := d.Profile()
+	pfl.Hosts = append(pfl.Hosts, &engine.DispatcherHostProfile{ID: "DSP_3"})
+	d.SetProfile(pfl)
+	for _, test := range []struct {
+		fails   int
+		routeID *string
+	}{
+		{fails: 2}, // answered by DSP_3
+		{fails: 0}, // answered by DSP_1
+		{fails: 1}, // answered by DSP_2
+		{fails: 0, routeID: utils.StringPointer("ROUTE_DEPTHS")}, // DSP_1 answers and is cached
+		{fails: 1, routeID: utils.StringPointer("ROUTE_DEPTHS")}, // the cached DSP_1 fails, then answers
+	} {
+		conn.fails = test.fails
+		var reply string
+		if err := d.Dispatch(nil, &utils.ArgDispatcher{RouteID: test.routeID}, utils.MetaAttributes,
+			utils.AttributeSv1Ping, &utils.CGREvent{}, &reply); err != nil {
+			t.Fatal(err)
+		}
+	}
+	exp := map[int]int{0: 2, 1: 2, 2: 1}
+	rply := d.FailoverDepthDistribution()
+	if !reflect.DeepEqual(exp, rply) {
+		t.Errorf("expecting: %+v, received: %+v", exp, rply)
+	}
+	rply[0] = 10
+	if rply := d.FailoverDepthDistribution(); !reflect.DeepEqual(exp, rply) {
+		t.Errorf("expecting a copy of the depths: %+v, received: %+v", exp, rply)
+	}
+	if rply := (*failoverDepths)(nil).FailoverDepthDistribution(); len(rply) != 0 {
+		t.Errorf("expecting no depths, received: %+v", rply)
+	}
+}
+
+func TestLibDispatcherWithHosts(t *testing.T) {
+	pfl := &engine.DispatcherProfile{
+		Tenant:   "cgrates.org",
+		ID:       "DSP_WITH_HOSTS",
+		Strategy: utils.MetaRoundRobin,
+		Hosts: engine.DispatcherHostProfiles{
+			{ID: "DSP_1", Weight: 10},
+			{ID: "DSP_2", Weight: 20},
+		},
+	}
+	d, err := newDispatcher(nil, pfl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.HostIDs() // the rotation does not change the hosts seen
+	var hostIDs []string
+	var weight float64
+	d.WithHosts(func(hosts engine.DispatcherHostProfiles) {
+		for _, host := range hosts {
+			hostIDs = append(hostIDs, host.ID)
+			weight += host.Weight
+		}
+	})
+	if exp := []string{"DSP_2", "DSP_1"}; !reflect.DeepEqual(exp, hostIDs) {
+		t.Errorf("expecting: %+v, received: %+v", exp, hostIDs)
+	}
+	if weight != 30 {
+		t.Errorf("expecting weight 30, received: %v", weight)
+	}
+	// a profile update replaces the hosts so a view taken before stays consistent
+	var before engine.DispatcherHostProfiles
+	d.WithHosts(func(hosts engine.DispatcherHostProfiles) { before = hosts })
+	pfl.Hosts = pfl.Hosts[:1]
+	d.SetProfile(pfl)
+	if len(before) != 2 {
+		t.Errorf("expecting the previous view unchanged, received: %+v", before.HostIDs())
+	}
+	d.WithHosts(func(hosts engine.DispatcherHostProfiles) { hostIDs = hosts.HostIDs() })
+	if exp := []string{"DSP_2"}; !reflect.DeepEqual(exp, hostIDs) {
+		t.Errorf("expecting: %+v, received: %+v", exp, hostIDs)
+	}
+}
+
+func BenchmarkLibDispatcherWithHosts(b *testing.B) {
+	d, err := newDispatcher(nil, testPriorityTiersProfile(utils.MetaWeight))
+	if err != nil {
+		b.Fatal(err)
+	}
+	var weight float64
+	sumWeights := func(hosts engine.DispatcherHostProfiles) {
+		for _, host := range hosts {
+			weight += host.Weight
+		}
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		d.WithHosts(sumWeights)
+	}
+}
+
+func BenchmarkLibDispatcherProfileHosts(b *testing.B) {
+	d, err := newDispatcher(nil, testPriorityTiersProfile(utils.MetaWeight))
+	if err != nil {
+		b.Fatal(err)
+	}
+	var weight float64
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, host := range d.Profile().Hosts {
+			weight += host.Weight
+		}
+	}
+}
+
+func TestLibDispatcherPreserveOrder(t *testing.T) {
+	newPfl := func(params map[string]interface{}) *engine.DispatcherProfile {
+		return &engine.DispatcherProfile{
+			Tenant:         "cgrates.org",
+			ID:             "DSP_PRESERVE_ORDER",
+			Strategy:       utils.MetaWeight,
+			StrategyParams: params,
+			Hosts: engine.DispatcherHostProfiles{
+				{ID: "DSP_2", Weight: 10},
+				{ID: "DSP_1", Weight: 30},
+				{ID: "DSP_3", Weight: 20},
+			},
+		}
+	}
+	d, err := newDispatcher(nil, newPfl(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := []string{"DSP_1", "DSP_3", "DSP_2"}; !reflect.DeepEqual(exp, d.HostIDs()) {
+		t.Errorf("expecting: %+v, received: %+v", exp, d.HostIDs())
+	}
+	configured := []string{"DSP_2", "DSP_1", "DSP_3"}
+	d.SetProfile(newPfl(map[string]interface{}{utils.MetaPreserveOrder: true}))
+	if rply := d.HostIDs(); !reflect.DeepEqual(configured, rply) {
+		t.Errorf("expecting: %+v, received: %+v", configured, rply)
+	}
+	pfl := newPfl(map[string]interface{}{utils.MetaPreserveOrder: "true"})
+	pfl.Strategy = utils.MetaRoundRobin
+	if d, err = newDispatcher(nil, pfl); err != nil {
+		t.Fatal(err)
+	}
+	for _, exp := range [][]string{configured, {"DSP_1", "DSP_3", "DSP_2"}, {"DSP_3", "DSP_2", "DSP_1"}} {
+		if rply := d.HostIDs(); !reflect.DeepEqual(exp, rply) {
+			t.Errorf("expecting: %+v, received: %+v", exp, rply)
+		}
+	}
+	// the priority tiers stay contiguous, the configured order being kept inside each tier
+	mixedPfl := func() *engine.DispatcherProfile {
+		pfl := newPfl(map[string]interface{}{utils.MetaPreserveOrder: true})
+		pfl.Hosts[0].Params = map[string]interface{}{utils.MetaPriority: 1}
+		pfl.Hosts = append(pfl.Hosts, &engine.DispatcherHostProfile{ID: "DSP_4", Weight: 40,
+			Params: map[string]interface{}{utils.MetaPriority: 1}})
+		return pfl
+	}
+	if d, err = newDispatcher(nil, mixedPfl()); err != nil {
+		t.Fatal(err)
+	}
+	if exp := []string{"DSP_1", "DSP_3", "DSP_2", "DSP_4"}; !reflect.DeepEqual(exp, d.HostIDs()) {
+		t.Errorf("expecting: %+v, received: %+v", exp, d.HostIDs())
+	}
+	d.SetProfile(newPfl(map[string]interface{}{utils.MetaPreserveOrder: true}))
+	d.SetProfile(mixedPfl())
+	if exp := []string{"DSP_1", "DSP_3", "DSP_2", "DSP_4"}; !reflect.DeepEqual(exp, d.HostIDs()) {
+		t.Errorf("expecting: %+v, received: %+v", exp, d.HostIDs())
+	}
+	// the order counts for the hash only when preserved
+	pfl = newPfl(map[string]interface{}{utils.MetaPreserveOrder: true})
+	swapped := newPfl(map[string]interface{}{utils.MetaPreserveOrder: true})
+	swapped.Hosts[0], swapped.Hosts[1] = swapped.Hosts[1], swapped.Hosts[0]
+	if profileHash(pfl) == profileHash(swapped) {
+		t.Error("expecting the hosts order to change the hash")
+	}
+	if _, err := newDispatcher(nil, newPfl(map[string]interface{}{utils.MetaPreserveOrder: "maybe"})); err == nil {
+		t.Error("expecting error for invalid " + utils.MetaPreserveOrder)
+	}
+}
+
+func TestLibDispatcherAffinityRoute(t *testing.T) {
+	d, err := newDispatcher(nil, &engine.DispatcherProfile{
+		Tenant:         "cgrates.org",
+		ID:             "DSP_AFFINITY",
+		Strategy:       utils.MetaWeight,
+		StrategyParams: map[string]interface{}{utils.MetaAffinityField: "CorrelationID"},
+		Hosts: engine.DispatcherHostProfiles{
+			{ID: "DSP_1"},
+			{ID: "DSP_2"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dp := d.(*WeightDispatcher).dispatcherProfile
+	ev := &utils.CGREvent{
+		Tenant: "cgrates.org",
+		ID:     "EV_AFFINITY",
+		Event:  map[string]interface{}{"CorrelationID": "call1"},
+	}
+	expRoute := "cgrates.org:CorrelationID:call1"
+	for _, subsys := range []string{utils.MetaAttributes, utils.MetaChargers, utils.MetaSessionS} {
+		routeID, rplySubsys := dp.affinityRoute(ev, nil, subsys)
+		if routeID == nil || *routeID != expRoute {
+			t.Errorf("for %s expecting route: %q, received: %v", subsys, expRoute, routeID)
+		}
+		if rplySubsys != utils.META_ANY {
+			t.Errorf("for %s expecting subsystem: %q, received: %q", subsys, utils.META_ANY, rplySubsys)
+		}
+	}
+	// an explicit RouteID wins
+	if routeID, subsys := dp.affinityRoute(ev, utils.StringPointer("ROUTE1"), utils.MetaChargers); *routeID != "ROUTE1" ||
+		subsys != utils.MetaChargers {
+		t.Errorf("expecting the RouteID kept, received: %q, %q", *routeID, subsys)
+	}
+	// without the field there is no affinity
+	ev.Event = map[string]interface{}{}
+	if routeID, subsys := dp.affinityRoute(ev, nil, utils.MetaChargers); routeID != nil ||
+		subsys != utils.MetaChargers {
+		t.Errorf("expecting no affinity, received: %v, %q", routeID, subsys)
+	}
+}
+
+func TestLibDispatcherCachedRoute(t *testing.T) {
+	routeID := utils.ConcatenatedKey("cgrates.org", "CorrelationID", "call1", utils.META_ANY)
+	if dH := cachedRoute(routeID, []string{"DSP_1"}); dH != nil {
+		t.Errorf("expecting no route, received: %+v", dH)
+	}
+	dH := &engine.DispatcherHost{Tenant: "cgrates.org", ID: "DSP_1"}
+	if err := engine.Cache.Set(utils.CacheDispatcherRoutes, routeID, dH,
+		nil, true, utils.EmptyString); err != nil {
+		t.Fatal(err)
+	}
+	defer engine.Cache.Remove(utils.CacheDispatcherRoutes, routeID, true, utils.EmptyString)
+	if rply := cachedRoute(routeID, []string{"DSP_2", "DSP_1"}); rply != dH {
+		t.Errorf("expecting: %+v, received: %+v", dH, rply)
+	}
+	// the route was discovered by a profile with other hosts
+	if rply := cachedRoute(routeID, []string{"DSP_2", "DSP_3"}); rply != nil {
+		t.Errorf("expecting the host outside the profile ignored, received: %+v", rply)
+	}
+	// a route to the self host is discovered again while there are other hosts
+	dspCfg := config.CgrConfig().DispatcherSCfg()
+	dspCfg.SelfHostID = "DSP_1"
+	if rply := cachedRoute(routeID, []string{"DSP_2", "DSP_1"}); rply != nil {
+		t.Errorf("expecting the self host ignored, received: %+v", rply)
+	}
+	if rply := cachedRoute(routeID, []string{"DSP_1"}); rply != dH {
+		t.Errorf("expecting: %+v, received: %+v", dH, rply)
+	}
+	dspCfg.SelfHostID = utils.EmptyString
+	d, err := newDispatcher(nil, &engine.DispatcherProfile{
+		Tenant:   "cgrates.org",
+		ID:       "DSP_OTHER",
+		Strategy: utils.MetaWeight,
+		Hosts:    engine.DispatcherHostProfiles{{ID: "DSP_2"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the hosts of the profile are used instead of the cached one
+	if err := d.Dispatch(nil, &utils.ArgDispatcher{RouteID: utils.StringPointer("cgrates.org:CorrelationID:call1")}, utils.META_ANY,
+		utils.AttributeSv1Ping, &utils.CGREvent{}, nil); err == nil ||
+		!strings.Contains(err.Error(), utils.ErrNoDatabaseConn.Error()) {
+		t.Errorf("expecting: %v, received: %v", utils.ErrNoDatabaseConn, err)
+	}
+}
+
+func TestLibDispatcherSetProfileUnchanged(t *testing.T) {
+	newPfl := func() *engine.DispatcherProfile {
+		return &engine.DispatcherProfile{
+			Tenant:   "cgrates.org",
+			ID:       "DSP_UNCHANGED",
+			Strategy: utils.MetaRatio,
+			Hosts: engine.DispatcherHostProfiles{
+				{ID: "DSP_2", Params: map[string]interface{}{utils.MetaRatio: 1}},
+				{ID: "DSP_1", Params: map[string]interface{}{utils.MetaRatio: 3}},
+			},
+		}
+	}
+	d, err := newDispatcher(nil, newPfl())
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts := make(map[string]int)
+	for i := 0; i < 400; i++ {
+		pfl := newPfl()
+		pfl.Weight = float64(i) // not affecting the dispatching
+		d.SetProfile(pfl)
+		counts[d.HostIDs()[0]]++
+	}
+	if exp := map[string]int{"DSP_1": 300, "DSP_2": 100}; !reflect.DeepEqual(exp, counts) {
+		t.Errorf("expecting: %+v, received: %+v", exp, counts)
+	}
+	if rply := d.Profile().Weight; rply != 399 {
+		t.Errorf("expecting the profile copy refreshed, received weight: %v", rply)
+	}
+	pfl := newPfl()
+	pfl.Hosts[0].Params[utils.MetaRatio] = 3
+	d.SetProfile(pfl)
+	counts = make(map[string]int)
+	for i := 0; i < 6; i++ {
+		counts[d.HostIDs()[0]]++
+	}
+	if exp := map[string]int{"DSP_1": 3, "DSP_2": 3}; !reflect.DeepEqual(exp, counts) {
+		t.Errorf("expecting: %+v, received: %+v", exp, counts)
+	}
+	// with *preserve_order the hosts regrouped per priority tier are still the same profile
+	preservePfl := func() *engine.DispatcherProfile {
+		return &engine.DispatcherProfile{
+			Tenant:         "cgrates.org",
+			ID:             "DSP_UNCHANGED",
+			Strategy:       utils.MetaRatio,
+			StrategyParams: map[string]interface{}{utils.MetaPreserveOrder: true},
+			Hosts: engine.DispatcherHostProfiles{
+				{ID: "DSP_A", Params: map[string]interface{}{utils.MetaPriority: 1, utils.MetaRatio: 1}},
+				{ID: "DSP_B", Params: map[string]interface{}{utils.MetaRatio: 3}},
+				{ID: "DSP_C", Params: map[string]interface{}{utils.MetaRatio: 1}},
+			},
+		}
+	}
+	if d, err = newDispatcher(nil, preservePfl()); err != nil {
+		t.Fatal(err)
+	}
+	counts = make(map[string]int)
+	for i := 0; i < 8; i++ {
+		d.SetProfile(preservePfl())
+		counts[d.HostIDs()[0]]++
+	}
+	if exp := map[string]int{"DSP_B": 6, "DSP_C": 2}; !reflect.DeepEqual(exp, counts) {
+		t.Errorf("expecting: %+v, received: %+v", exp, counts)
+	}
+}
+
+func TestLibDispatcherSelfHostLast(t *testing.T) {
+	hostIDs := []string{"DSP_SELF", "DSP_1", "DSP_2"}
+	if rply := selfHostLast(hostIDs); !reflect.DeepEqual(hostIDs, rply) {
+		t.Errorf("expecting no change without self_host_id, received: %+v", rply)
+	}
+	dspCfg := config.CgrConfig().DispatcherSCfg()
+	dspCfg.SelfHostID = "DSP_SELF"
+	defer func() { dspCfg.SelfHostID = utils.EmptyString }()
+	exp := []string{"DSP_1", "DSP_2", "DSP_SELF"}
+	if rply := selfHostLast(hostIDs); !reflect.DeepEqual(exp, rply) {
+		t.Errorf("expecting: %+v, received: %+v", exp, rply)
+	}
+	if exp := []string{"DSP_SELF", "DSP_1", "DSP_2"}; !reflect.DeepEqual(exp, hostIDs) {
+		t.Errorf("expecting the hosts unchanged: %+v, received: %+v", exp, hostIDs)
+	}
+	// the only host left is still used
+	if exp := []string{"DSP_SELF"}; !reflect.DeepEqual(exp, selfHostLast(exp)) {
+		t.Errorf("expecting: %+v, received: %+v", exp, selfHostLast(exp))
+	}
+	if exp := []string{"DSP_1", "DSP_2"}; !reflect.DeepEqual(exp, selfHostLast(exp)) {
+		t.Errorf("expecting: %+v, received: %+v", exp, selfHostLast(exp))
+	}
+}
+
+func TestLibDispatcherRatioPercentages(t *testing.T) {
+	newPfl := func(pctMode string, pcts ...int) *engine.DispatcherProfile {
+		pfl := &engine.DispatcherProfile{
+			Tenant:         "cgrates.org",
+			ID:             "DSP_PERCENTAGES",
+			Strategy:       utils.MetaRatio,
+			StrategyParams: map[string]interface{}{utils.MetaPercentages: pctMode},
+		}
+		for i, pct := range pcts {
+			pfl.Hosts = append(pfl.Hosts, &engine.DispatcherHostProfile{
+				ID:     fmt.Sprintf("DSP_%d", i+1),
+				Weight: float64(len(pcts) - i),
+				Params: map[string]interface{}{utils.MetaRatio: pct},
+			})
+		}
+		return pfl
+	}
+	leads := func(d Dispatcher) (counts map[string]int) {
+		counts = make(map[string]int)
+		for i := 0; i < 100; i++ {
+			counts[d.HostIDs()[0]]++
+		}
+		return
+	}
+	for _, pctMode := range []string{utils.MetaStrict, utils.MetaNormalize} {
+		d, err := newDispatcher(nil, newPfl(pctMode, 50, 30, 20))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if exp := map[string]int{"DSP_1": 50, "DSP_2": 30, "DSP_3": 20}; !reflect.DeepEqual(exp, leads(d)) {
+			t.Errorf("for %s expecting: %+v, received: %+v", pctMode, exp, leads(d))
+		}
+	}
+	for _, pcts := range [][]int{{50, 30, 10}, {50, 30, 30}} {
+		if _, err := newDispatcher(nil, newPfl(utils.MetaStrict, pcts...)); err == nil {
+			t.Errorf("expecting error for %s percentages %+v", utils.MetaStrict, pcts)
+		}
+	}
+	for _, test := range []struct {
+		pcts []int
+		exp  map[string]int
+	}{
+		{pcts: []int{5, 3, 1}, exp: map[string]int{"DSP_1": 56, "DSP_2": 33, "DSP_3": 11}},
+		{pcts: []int{60, 60, 30}, exp: map[string]int{"DSP_1": 40, "DSP_2": 40, "DSP_3": 20}},
+		{pcts: []int{1, 1, 1}, exp: map[string]int{"DSP_1": 34, "DSP_2": 33, "DSP_3": 33}},
+		// scaling these to percentages overflows int64
+		{pcts: []int{1e17, 1e17}, exp: map[string]int{"DSP_1": 50, "DSP_2": 50}},
+		{pcts: []int{9e17, 3e17}, exp: map[string]int{"DSP_1": 75, "DSP_2": 25}},
+	} {
+		d, err := newDispatcher(nil, newPfl(utils.MetaNormalize, test.pcts...))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rply := leads(d); !reflect.DeepEqual(test.exp, rply) {
+			t.Errorf("for %+v expecting: %+v, received: %+v", test.pcts, test.exp, rply)
+		}
+	}
+	if _, err := newDispatcher(nil, newPfl("*maybe", 100)); err == nil {
+		t.Errorf("expecting error for unsupported %s", utils.MetaPercentages)
+	}
+}
+
+func TestLibDispatcherRatioLimits(t *testing.T) {
+	ratioHosts := func(ratios ...interface{}) (hosts engine.DispatcherHostProfiles) {
+		for i, ratio := range ratios {
+			hosts = append(hosts, &engine.DispatcherHostProfile{
+				ID:     fmt.Sprintf("DSP_%d", i+1),
+				Params: map[string]interface{}{utils.MetaRatio: ratio},
+			})
+		}
+		return
+	}
+	// the ratios are reduced by their greatest common divisor
+	cycles, cycleLen, err := ratioCycles(ratioHosts(20000000000, 40000000000, "20000000000"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := [][]int{{1, 0, 2, 1}}; !reflect.DeepEqual(exp, cycles) || cycleLen != 4 {
+		t.Errorf("expecting: %+v with length 4, received: %+v with length %d", exp, cycles, cycleLen)
+	}
+	// too long cycles are refused instead of allocated
+	for _, hosts := range []engine.DispatcherHostProfiles{
+		ratioHosts(10000000000, 1),
+		ratioHosts(math.MaxInt64, math.MaxInt64),
+	} {
+		if _, _, err := ratioCycles(hosts, nil); err == nil {
+			t.Errorf("expecting error for ratios: %+v", utils.ToJSON(hosts))
+		}
+	}
+	hosts := ratioHosts(9973, 1, 9967, 1, 9949, 1)
+	for i, host := range hosts {
+		host.Params[utils.MetaPriority] = i / 2
+	}
+	if _, _, err := ratioCycles(hosts, nil); err == nil {
+		t.Error("expecting error for tiers not combining in a reasonable cycle")
+	}
+	pfl := &engine.DispatcherProfile{
+		Tenant:   "cgrates.org",
+		ID:       "DSP_RATIO_LIMITS",
+		Strategy: utils.MetaRatio,
+		Hosts:    ratioHosts(10000000000, 1),
+	}
+	if _, err := newDispatcher(nil, pfl); err == nil {
+		t.Error("expecting error for too long cycle")
+	}
+}
+
+func TestLibDispatcherValidate(t *testing.T) {
+	for _, strategy := range []string{utils.MetaWeight, utils.MetaRandom, utils.MetaRoundRobin,
+		utils.MetaRatio, utils.MetaBroadcast, utils.MetaLoad} {
+		d, err := newDispatcher(nil, testPriorityTiersProfile(strategy))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := d.Validate(); err != nil {
+			t.Errorf("for %s received error: %v", strategy, err)
+		}
+		empty, err := newDispatcher(nil, &engine.DispatcherProfile{Tenant: "cgrates.org", ID: "DSP_EMPTY", Strategy: strategy})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := empty.Validate(); err == nil || err.Error() != "no hosts" {
+			t.Errorf("for %s expecting no hosts error, received: %v", strategy, err)
+		}
+	}
+	d, err := newDispatcher(nil, testPriorityTiersProfile(utils.MetaRandom))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rd := d.(*RandomDispatcher)
+	rd.hosts = append(rd.hosts, rd.hosts[0])
+	if err := d.Validate(); err == nil {
+		t.Error("expecting error for duplicated hosts")
+	}
+	if d, err = newDispatcher(nil, testPriorityTiersProfile(utils.MetaWeight)); err != nil {
+		t.Fatal(err)
+	}
+	d.(*WeightDispatcher).hosts[0].Params = map[string]interface{}{utils.MetaPriority: "high"}
+	if err := d.Validate(); err == nil {
+		t.Error("expecting error for invalid priority")
+	}
+	if d, err = newDispatcher(nil, testPriorityTiersProfile(utils.MetaRoundRobin)); err != nil {
+		t.Fatal(err)
+	}
+	d.(*RoundRobinDispatcher).hostIdx = -1
+	if err := d.Validate(); err == nil {
+		t.Error("expecting error for negative round robin index")
+	}
+	if d, err = newDispatcher(nil, testPriorityTiersProfile(utils.MetaLoad)); err != nil {
+		t.Fatal(err)
+	}
+	ls := d.(*WeightDispatcher).strategy.(*loadStrategyDispatcher)
+	ls.hosts = ls.hosts[1:]
+	if err := d.Validate(); err == nil {
+		t.Error("expecting error for *load hosts out of sync")
+	}
+	for _, corrupt := range []func(*RatioDispatcher){
+		func(rd *RatioDispatcher) { rd.cycles = rd.cycles[1:] },
+		func(rd *RatioDispatcher) { rd.cycles[0] = []int{len(rd.hosts)} },
+		func(rd *RatioDispatcher) { rd.cycles[0] = nil },
+		func(rd *RatioDispatcher) { rd.cycleIdx = rd.cycleLen },
+	} {
+		if d, err = newDispatcher(nil, testPriorityTiersProfile(utils.MetaRatio)); err != nil {
+			t.Fatal(err)
+		}
+		corrupt(d.(*RatioDispatcher))
+		if err := d.Validate(); err == nil {
+			t.Error("expecting error for corrupted *ratio cycles")
+		}
+	}
+	if d, err = newDispatcher(nil, testPriorityTiersProfile(utils.MetaBroadcast)); err != nil {
+		t.Fatal(err)
+	}
+	d.(*BroadcastDispatcher).strategy.(*brodcastStrategyDispatcher).quorum = -1
+	if err := d.Validate(); err == nil {
+		t.Error("expecting error for negative quorum")
+	}
+}
+
+func TestLibDispatcherKillSwitch(t *testing.T) {
+	for _, strategy := range []string{utils.MetaWeight, utils.MetaRandom, utils.MetaRoundRobin,
+		utils.MetaRatio, utils.MetaBroadcast, utils.MetaLoad} {
+		pfl := testPriorityTiersProfile(strategy)
+		pfl.StrategyParams = map[string]interface{}{utils.MetaKillSwitch: utils.EmptyString}
+		d, err := newDispatcher(nil, pfl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := d.Dispatch(nil, nil, utils.MetaAttributes, utils.AttributeSv1Ping,
+			&utils.CGREvent{}, nil); err == nil || !strings.Contains(err.Error(), utils.ErrNoDatabaseConn.Error()) {
+			t.Errorf("for %s expecting: %v, received: %v", strategy, utils.ErrNoDatabaseConn, err)
+		}
+		if dgs := d.Degradations(); dgs[DegradedKillSwitch] != 0 {
+			t.Errorf("for %s unexpected degradations: %+v", strategy, dgs)
+		}
+
+		pfl = testPriorityTiersProfile(strategy)
+		pfl.StrategyParams = map[string]interface{}{utils.MetaKillSwitch: utils.MetaReject}
+		if d, err = newDispatcher(nil, pfl); err != nil {
+			t.Fatal(err)
+		}
+		if err := d.Validate(); err != nil {
+			t.Errorf("for %s received error: %v", strategy, err)
+		}
+		expErr := utils.NewErrDispatcherS(utils.ErrDispatcherDisabled)
+		for i := 0; i < 2; i++ {
+			if err := d.Dispatch(nil, &utils.ArgDispatcher{RouteID: utils.StringPointer("route1")}, utils.MetaAttributes, utils.AttributeSv1Ping,
+				&utils.CGREvent{}, nil); err == nil || err.Error() != expErr.Error() {
+				t.Errorf("for %s expecting: %v, received: %v", strategy, expErr, err)
+			}
+		}
+		if dgs := d.Degradations(); dgs[DegradedKillSwitch] != 2 {
+			t.Errorf("for %s expecting 2 kill switch degradations, received: %+v", strategy, dgs)
+		}
+
+		pfl = testPriorityTiersProfile(strategy)
+		pfl.StrategyParams = map[string]interface{}{utils.MetaKillSwitch: "DSP_3"}
+		if d, err = newDispatcher(nil, pfl); err != nil {
+			t.Fatal(err)
+		}
+		if err := d.Validate(); err != nil {
+			t.Errorf("for %s received error: %v", strategy, err)
+		}
+		if err := d.Dispatch(nil, nil, utils.MetaAttributes, utils.AttributeSv1Ping,
+			&utils.CGREvent{}, nil); err == nil || !strings.Contains(err.Error(), utils.ErrNoDatabaseConn.Error()) {
+			t.Errorf("for %s expecting: %v, received: %v", strategy, utils.ErrNoDatabaseConn, err)
+		}
+		if dgs := d.Degradations(); dgs[DegradedKillSwitch] != 1 {
+			t.Errorf("for %s expecting 1 kill switch degradation, received: %+v", strategy, dgs)
+		}
+
+		pfl = testPriorityTiersProfile(strategy)
+		pfl.StrategyParams = map[string]interface{}{utils.MetaKillSwitch: "DSP_UNKNOWN"}
+		if d, err = newDispatcher(nil, pfl); err != nil {
+			t.Fatal(err)
+		}
+		if err := d.Validate(); err == nil {
+			t.Errorf("for %s expecting error for kill switch host outside the profile", strategy)
+		}
+	}
+	d, err := newDispatcher(nil, &engine.DispatcherProfile{
+		Tenant:         "cgrates.org",
+		ID:             "DSP_KS",
+		Strategy:       utils.MetaWeight,
+		StrategyParams: map[string]interface{}{utils.MetaKillSwitch: "DSP_2"},
+		Hosts:          engine.DispatcherHostProfiles{{ID: "DSP_1", Weight: 20}, {ID: "DSP_2", Weight: 10}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := []string{"DSP_2"}
+	if hostIDs, active, err := d.(*WeightDispatcher).killSwitch(); err != nil || !active ||
+		!reflect.DeepEqual(exp, hostIDs) {
+		t.Errorf("expecting: %+v, received: %+v %v %v", exp, hostIDs, active, err)
+	}
+}
+
+func TestLibDispatcherStatus(t *testing.T) {
+	for _, strategy := range []string{utils.MetaWeight, utils.MetaRandom, utils.MetaRoundRobin,
+		utils.MetaRatio, utils.MetaBroadcast, utils.MetaLoad} {
+		pfl := testPriorityTiersProfile(strategy)
+		pfl.Hosts = append(pfl.Hosts, &engine.DispatcherHostProfile{ID: "DSP_1", Weight: 5})
+		d, err := newDispatcher(nil, pfl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		exp := DispatcherStatus{
+			Tenant:       "cgrates.org",
+			ID:           "DSP_TIERS",
+			Strategy:     strategy,
+			Hosts:        6,
+			Degradations: map[string]int64{},
+		}
+		if st := d.Status(); !reflect.DeepEqual(exp, st) {
+			t.Errorf("for %s expecting: %+v, received: %+v", strategy, exp, st)
+		}
+
+		pfl = testPriorityTiersProfile(strategy)
+		pfl.StrategyParams = map[string]interface{}{utils.MetaKillSwitch: utils.MetaReject}
+		d.SetProfile(pfl)
+		d.Dispatch(nil, nil, utils.MetaAttributes, utils.AttributeSv1Ping, &utils.CGREvent{}, nil)
+		exp.KillSwitch = utils.MetaReject
+		exp.Degraded = true
+		exp.Degradations = map[string]int64{DegradedKillSwitch: 1}
+		if st := d.Status(); !reflect.DeepEqual(exp, st) {
+			t.Errorf("for %s expecting: %+v, received: %+v", strategy, exp, st)
+		}
+
+		pfl = testPriorityTiersProfile(strategy)
+		pfl.StrategyParams = map[string]interface{}{utils.MetaKillSwitch: "DSP_2"}
+		pfl.Hosts = pfl.Hosts[1:]
+		d.SetProfile(pfl)
+		exp.Hosts = 5
+		exp.KillSwitch = "DSP_2"
+		if st := d.Status(); !reflect.DeepEqual(exp, st) {
+			t.Errorf("for %s expecting: %+v, received: %+v", strategy, exp, st)
+		}
+	}
+	// an old degradation is still counted but does not keep the dispatcher degraded
+	d, err := newDispatcher(nil, testPriorityTiersProfile(utils.MetaRandom))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sd := d.(*RandomDispatcher).strategyDegradations
+	sd.degraded(DegradedMissingEventID)
+	if st := d.Status(); !st.Degraded {
+		t.Errorf("expecting degraded status, received: %+v", st)
+	}
+	sd.last[DegradedMissingEventID] = time.Now().Add(-2 * degradedStatusWindow)
+	if st := d.Status(); st.Degraded || st.Degradations[DegradedMissingEventID] != 1 {
+		t.Errorf("expecting not degraded status with the degradation counted, received: %+v", st)
+	}
+}
+
+func TestLibDispatcherWeightedOrder(t *testing.T) {
+	hosts := engine.DispatcherHostProfiles{
+		{ID: "DSP_1", Weight: 1},
+		{ID: "DSP_2", Weight: 100, Params: map[string]interface{}{utils.MetaPriority: 1}},
+		{ID: "DSP_3"},
+		{ID: "DSP_4", Weight: 10},
+		{ID: "DSP_5", Weight: 50},
+	}
+	rply := weightedOrder(hosts)
+	sort.Strings(rply)
+	if exp := []string{"DSP_1", "DSP_2", "DSP_3", "DSP_4", "DSP_5"}; !reflect.DeepEqual(exp, rply) {
+		t.Errorf("expecting: %+v, received: %+v", exp, rply)
+	}
+	if rply := weightedOrder(nil); len(rply) != 0 {
+		t.Errorf("expecting no hosts, received: %+v", rply)
+	}
+	// the average position follows the weights, the host without weight being always last
+	const runs = 5000
+	positions := make(map[string]int)
+	for i := 0; i < runs; i++ {
+		hostIDs := weightedOrder(hosts)
+		if len(hostIDs) != len(hosts) {
+			t.Fatalf("expecting %d hosts, received: %+v", len(hosts), hostIDs)
+		}
+		if hostIDs[len(hostIDs)-1] != "DSP_3" {
+			t.Fatalf("expecting DSP_3 last, received: %+v", hostIDs)
+		}
+		for pos, hostID := range hostIDs {
+			positions[hostID] += pos
+		}
+	}
+	for _, pair := range [][2]string{{"DSP_2", "DSP_5"}, {"DSP_5", "DSP_4"}, {"DSP_4", "DSP_1"}} {
+		if positions[pair[0]] >= positions[pair[1]] {
+			t.Errorf("expecting %s before %s on average, received positions: %+v", pair[0], pair[1], positions)
+		}
+	}
+	if avg := float64(positions["DSP_2"]) / runs; avg > 0.6 {
+		t.Errorf("expecting DSP_2 mostly first, received average position: %v", avg)
+	}
+
+	for _, strategy := range []string{utils.MetaWeight, utils.MetaRandom, utils.MetaRoundRobin,
+		utils.MetaRatio, utils.MetaBroadcast, utils.MetaLoad} {
+		d, err := newDispatcher(nil, testPriorityTiersProfile(strategy))
+		if err != nil {
+			t.Fatal(err)
+		}
+		rply := WeightedOrder(d)
+		sort.Strings(rply)
+		if exp := []string{"DSP_1", "DSP_2", "DSP_3", "DSP_4", "DSP_5", "DSP_6"}; !reflect.DeepEqual(exp, rply) {
+			t.Errorf("for %s expecting: %+v, received: %+v", strategy, exp, rply)
+		}
+	}
+}
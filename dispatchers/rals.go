@@ -38,11 +38,7 @@ func (dS *DispatcherService) RALsV1Ping(args *utils.CGREventWithArgDispatcher, r
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(args.CGREvent, utils.MetaRALs, routeID,
+	return dS.Dispatch(args.CGREvent, utils.MetaRALs, args.ArgDispatcher,
 		utils.RALsV1Ping, args, rpl)
 }
 
@@ -58,10 +54,6 @@ func (dS *DispatcherService) RALsV1GetRatingPlansCost(args *utils.RatingPlanCost
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: tenant}, utils.MetaRALs, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: tenant}, utils.MetaRALs, args.ArgDispatcher,
 		utils.RALsV1GetRatingPlansCost, args, rpl)
 }
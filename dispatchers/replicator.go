@@ -39,11 +39,7 @@ func (dS *DispatcherService) ReplicatorSv1Ping(args *utils.CGREventWithArgDispat
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(args.CGREvent, utils.MetaReplicator, routeID,
+	return dS.Dispatch(args.CGREvent, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1Ping, args, rpl)
 }
 
@@ -61,11 +57,7 @@ func (dS *DispatcherService) ReplicatorSv1GetAccount(args *utils.StringWithApiKe
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaReplicator, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1GetAccount, args, rpl)
 }
 
@@ -83,11 +75,7 @@ func (dS *DispatcherService) ReplicatorSv1GetDestination(args *utils.StringWithA
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaReplicator, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1GetDestination, args, rpl)
 }
 
@@ -105,11 +93,7 @@ func (dS *DispatcherService) ReplicatorSv1GetReverseDestination(args *utils.Stri
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaReplicator, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1GetReverseDestination, args, rpl)
 }
 
@@ -127,11 +111,10 @@ func (dS *DispatcherService) ReplicatorSv1GetStatQueue(args *utils.TenantIDWithA
 			return
 		}
 	}
-	routeID := args.ArgDispatcher.RouteID
 	return dS.Dispatch(&utils.CGREvent{
 		Tenant: tnt,
 		ID:     args.ID,
-	}, utils.MetaReplicator, routeID, utils.ReplicatorSv1GetStatQueue, args, reply)
+	}, utils.MetaReplicator, args.ArgDispatcher, utils.ReplicatorSv1GetStatQueue, args, reply)
 }
 
 func (dS *DispatcherService) ReplicatorSv1GetFilter(args *utils.TenantIDWithArgDispatcher, reply *engine.Filter) (err error) {
@@ -148,11 +131,10 @@ func (dS *DispatcherService) ReplicatorSv1GetFilter(args *utils.TenantIDWithArgD
 			return
 		}
 	}
-	routeID := args.ArgDispatcher.RouteID
 	return dS.Dispatch(&utils.CGREvent{
 		Tenant: tnt,
 		ID:     args.ID,
-	}, utils.MetaReplicator, routeID, utils.ReplicatorSv1GetFilter, args, reply)
+	}, utils.MetaReplicator, args.ArgDispatcher, utils.ReplicatorSv1GetFilter, args, reply)
 }
 
 func (dS *DispatcherService) ReplicatorSv1GetThreshold(args *utils.TenantIDWithArgDispatcher, reply *engine.Threshold) (err error) {
@@ -169,11 +151,10 @@ func (dS *DispatcherService) ReplicatorSv1GetThreshold(args *utils.TenantIDWithA
 			return
 		}
 	}
-	routeID := args.ArgDispatcher.RouteID
 	return dS.Dispatch(&utils.CGREvent{
 		Tenant: tnt,
 		ID:     args.ID,
-	}, utils.MetaReplicator, routeID, utils.ReplicatorSv1GetThreshold, args, reply)
+	}, utils.MetaReplicator, args.ArgDispatcher, utils.ReplicatorSv1GetThreshold, args, reply)
 }
 
 func (dS *DispatcherService) ReplicatorSv1GetThresholdProfile(args *utils.TenantIDWithArgDispatcher, reply *engine.ThresholdProfile) (err error) {
@@ -190,11 +171,10 @@ func (dS *DispatcherService) ReplicatorSv1GetThresholdProfile(args *utils.Tenant
 			return
 		}
 	}
-	routeID := args.ArgDispatcher.RouteID
 	return dS.Dispatch(&utils.CGREvent{
 		Tenant: tnt,
 		ID:     args.ID,
-	}, utils.MetaReplicator, routeID, utils.ReplicatorSv1GetThresholdProfile, args, reply)
+	}, utils.MetaReplicator, args.ArgDispatcher, utils.ReplicatorSv1GetThresholdProfile, args, reply)
 }
 
 func (dS *DispatcherService) ReplicatorSv1GetStatQueueProfile(args *utils.TenantIDWithArgDispatcher, reply *engine.StatQueueProfile) (err error) {
@@ -211,11 +191,10 @@ func (dS *DispatcherService) ReplicatorSv1GetStatQueueProfile(args *utils.Tenant
 			return
 		}
 	}
-	routeID := args.ArgDispatcher.RouteID
 	return dS.Dispatch(&utils.CGREvent{
 		Tenant: tnt,
 		ID:     args.ID,
-	}, utils.MetaReplicator, routeID, utils.ReplicatorSv1GetStatQueueProfile, args, reply)
+	}, utils.MetaReplicator, args.ArgDispatcher, utils.ReplicatorSv1GetStatQueueProfile, args, reply)
 }
 
 func (dS *DispatcherService) ReplicatorSv1GetTiming(args *utils.StringWithApiKey, rpl *utils.TPTiming) (err error) {
@@ -232,11 +211,7 @@ func (dS *DispatcherService) ReplicatorSv1GetTiming(args *utils.StringWithApiKey
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaReplicator, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1GetTiming, args, rpl)
 }
 
@@ -254,11 +229,10 @@ func (dS *DispatcherService) ReplicatorSv1GetResource(args *utils.TenantIDWithAr
 			return
 		}
 	}
-	routeID := args.ArgDispatcher.RouteID
 	return dS.Dispatch(&utils.CGREvent{
 		Tenant: tnt,
 		ID:     args.ID,
-	}, utils.MetaReplicator, routeID, utils.ReplicatorSv1GetResource, args, reply)
+	}, utils.MetaReplicator, args.ArgDispatcher, utils.ReplicatorSv1GetResource, args, reply)
 }
 
 func (dS *DispatcherService) ReplicatorSv1GetResourceProfile(args *utils.TenantIDWithArgDispatcher, reply *engine.ResourceProfile) (err error) {
@@ -275,11 +249,10 @@ func (dS *DispatcherService) ReplicatorSv1GetResourceProfile(args *utils.TenantI
 			return
 		}
 	}
-	routeID := args.ArgDispatcher.RouteID
 	return dS.Dispatch(&utils.CGREvent{
 		Tenant: tnt,
 		ID:     args.ID,
-	}, utils.MetaReplicator, routeID, utils.ReplicatorSv1GetResourceProfile, args, reply)
+	}, utils.MetaReplicator, args.ArgDispatcher, utils.ReplicatorSv1GetResourceProfile, args, reply)
 }
 
 func (dS *DispatcherService) ReplicatorSv1GetActionTriggers(args *utils.StringWithApiKey, rpl *engine.ActionTriggers) (err error) {
@@ -296,11 +269,7 @@ func (dS *DispatcherService) ReplicatorSv1GetActionTriggers(args *utils.StringWi
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaReplicator, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1GetActionTriggers, args, rpl)
 }
 
@@ -318,11 +287,7 @@ func (dS *DispatcherService) ReplicatorSv1GetSharedGroup(args *utils.StringWithA
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaReplicator, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1GetSharedGroup, args, rpl)
 }
 
@@ -340,11 +305,7 @@ func (dS *DispatcherService) ReplicatorSv1GetActions(args *utils.StringWithApiKe
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaReplicator, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1GetActions, args, rpl)
 }
 
@@ -362,11 +323,7 @@ func (dS *DispatcherService) ReplicatorSv1GetActionPlan(args *utils.StringWithAp
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaReplicator, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1GetActionPlan, args, rpl)
 }
 
@@ -384,11 +341,7 @@ func (dS *DispatcherService) ReplicatorSv1GetAllActionPlans(args *utils.StringWi
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaReplicator, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1GetAllActionPlans, args, rpl)
 }
 
@@ -406,11 +359,7 @@ func (dS *DispatcherService) ReplicatorSv1GetAccountActionPlans(args *utils.Stri
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaReplicator, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1GetAccountActionPlans, args, rpl)
 }
 
@@ -428,11 +377,7 @@ func (dS *DispatcherService) ReplicatorSv1GetRatingPlan(args *utils.StringWithAp
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaReplicator, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1GetRatingPlan, args, rpl)
 }
 
@@ -450,11 +395,7 @@ func (dS *DispatcherService) ReplicatorSv1GetRatingProfile(args *utils.StringWit
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaReplicator, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1GetRatingProfile, args, rpl)
 }
 
@@ -472,11 +413,10 @@ func (dS *DispatcherService) ReplicatorSv1GetRouteProfile(args *utils.TenantIDWi
 			return
 		}
 	}
-	routeID := args.ArgDispatcher.RouteID
 	return dS.Dispatch(&utils.CGREvent{
 		Tenant: tnt,
 		ID:     args.ID,
-	}, utils.MetaReplicator, routeID, utils.ReplicatorSv1GetRouteProfile, args, reply)
+	}, utils.MetaReplicator, args.ArgDispatcher, utils.ReplicatorSv1GetRouteProfile, args, reply)
 }
 
 func (dS *DispatcherService) ReplicatorSv1GetAttributeProfile(args *utils.TenantIDWithArgDispatcher, reply *engine.AttributeProfile) (err error) {
@@ -493,11 +433,10 @@ func (dS *DispatcherService) ReplicatorSv1GetAttributeProfile(args *utils.Tenant
 			return
 		}
 	}
-	routeID := args.ArgDispatcher.RouteID
 	return dS.Dispatch(&utils.CGREvent{
 		Tenant: tnt,
 		ID:     args.ID,
-	}, utils.MetaReplicator, routeID, utils.ReplicatorSv1GetAttributeProfile, args, reply)
+	}, utils.MetaReplicator, args.ArgDispatcher, utils.ReplicatorSv1GetAttributeProfile, args, reply)
 }
 
 func (dS *DispatcherService) ReplicatorSv1GetChargerProfile(args *utils.TenantIDWithArgDispatcher, reply *engine.ChargerProfile) (err error) {
@@ -514,11 +453,10 @@ func (dS *DispatcherService) ReplicatorSv1GetChargerProfile(args *utils.TenantID
 			return
 		}
 	}
-	routeID := args.ArgDispatcher.RouteID
 	return dS.Dispatch(&utils.CGREvent{
 		Tenant: tnt,
 		ID:     args.ID,
-	}, utils.MetaReplicator, routeID, utils.ReplicatorSv1GetChargerProfile, args, reply)
+	}, utils.MetaReplicator, args.ArgDispatcher, utils.ReplicatorSv1GetChargerProfile, args, reply)
 }
 
 func (dS *DispatcherService) ReplicatorSv1GetDispatcherProfile(args *utils.TenantIDWithArgDispatcher, reply *engine.DispatcherProfile) (err error) {
@@ -535,11 +473,10 @@ func (dS *DispatcherService) ReplicatorSv1GetDispatcherProfile(args *utils.Tenan
 			return
 		}
 	}
-	routeID := args.ArgDispatcher.RouteID
 	return dS.Dispatch(&utils.CGREvent{
 		Tenant: tnt,
 		ID:     args.ID,
-	}, utils.MetaReplicator, routeID, utils.ReplicatorSv1GetDispatcherProfile, args, reply)
+	}, utils.MetaReplicator, args.ArgDispatcher, utils.ReplicatorSv1GetDispatcherProfile, args, reply)
 }
 
 func (dS *DispatcherService) ReplicatorSv1GetDispatcherHost(args *utils.TenantIDWithArgDispatcher, reply *engine.DispatcherHost) (err error) {
@@ -556,11 +493,10 @@ func (dS *DispatcherService) ReplicatorSv1GetDispatcherHost(args *utils.TenantID
 			return
 		}
 	}
-	routeID := args.ArgDispatcher.RouteID
 	return dS.Dispatch(&utils.CGREvent{
 		Tenant: tnt,
 		ID:     args.ID,
-	}, utils.MetaReplicator, routeID, utils.ReplicatorSv1GetDispatcherHost, args, reply)
+	}, utils.MetaReplicator, args.ArgDispatcher, utils.ReplicatorSv1GetDispatcherHost, args, reply)
 }
 
 func (dS *DispatcherService) ReplicatorSv1GetItemLoadIDs(args *utils.StringWithApiKey, rpl *map[string]int64) (err error) {
@@ -577,11 +513,7 @@ func (dS *DispatcherService) ReplicatorSv1GetItemLoadIDs(args *utils.StringWithA
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaReplicator, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1GetItemLoadIDs, args, rpl)
 }
 
@@ -599,11 +531,7 @@ func (dS *DispatcherService) ReplicatorSv1GetFilterIndexes(args *utils.GetFilter
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaReplicator, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1GetFilterIndexes, args, rpl)
 }
 
@@ -621,11 +549,7 @@ func (dS *DispatcherService) ReplicatorSv1MatchFilterIndex(args *utils.MatchFilt
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaReplicator, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1MatchFilterIndex, args, rpl)
 }
 
@@ -643,11 +567,7 @@ func (dS *DispatcherService) ReplicatorSv1SetThresholdProfile(args *engine.Thres
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.ThresholdProfile.Tenant}, utils.MetaReplicator, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.ThresholdProfile.Tenant}, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1SetThresholdProfile, args, rpl)
 }
 
@@ -665,11 +585,7 @@ func (dS *DispatcherService) ReplicatorSv1SetThreshold(args *engine.ThresholdWit
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1SetThreshold, args, rpl)
 }
 
@@ -687,11 +603,7 @@ func (dS *DispatcherService) ReplicatorSv1SetFilterIndexes(args *utils.SetFilter
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1SetFilterIndexes, args, rpl)
 }
 
@@ -709,11 +621,7 @@ func (dS *DispatcherService) ReplicatorSv1SetDestination(args *engine.Destinatio
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1SetDestination, args, rpl)
 }
 
@@ -731,11 +639,7 @@ func (dS *DispatcherService) ReplicatorSv1SetAccount(args *engine.AccountWithArg
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: tenant}, utils.MetaReplicator, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: tenant}, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1SetAccount, args, rpl)
 }
 
@@ -753,11 +657,7 @@ func (dS *DispatcherService) ReplicatorSv1SetReverseDestination(args *engine.Des
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1SetReverseDestination, args, rpl)
 }
 
@@ -775,11 +675,7 @@ func (dS *DispatcherService) ReplicatorSv1SetStatQueue(args *engine.StoredStatQu
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1SetStatQueue, args, rpl)
 }
 
@@ -797,11 +693,7 @@ func (dS *DispatcherService) ReplicatorSv1SetFilter(args *engine.FilterWithArgDi
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1SetFilter, args, rpl)
 }
 
@@ -819,11 +711,7 @@ func (dS *DispatcherService) ReplicatorSv1SetStatQueueProfile(args *engine.StatQ
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1SetStatQueueProfile, args, rpl)
 }
 
@@ -841,11 +729,7 @@ func (dS *DispatcherService) ReplicatorSv1SetTiming(args *utils.TPTimingWithArgD
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1SetTiming, args, rpl)
 }
 
@@ -863,11 +747,7 @@ func (dS *DispatcherService) ReplicatorSv1SetResource(args *engine.ResourceWithA
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1SetResource, args, rpl)
 }
 
@@ -885,11 +765,7 @@ func (dS *DispatcherService) ReplicatorSv1SetResourceProfile(args *engine.Resour
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1SetResourceProfile, args, rpl)
 }
 
@@ -907,11 +783,7 @@ func (dS *DispatcherService) ReplicatorSv1SetActionTriggers(args *engine.SetActi
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1SetActionTriggers, args, rpl)
 }
 
@@ -929,11 +801,7 @@ func (dS *DispatcherService) ReplicatorSv1SetSharedGroup(args *engine.SharedGrou
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1SetSharedGroup, args, rpl)
 }
 
@@ -951,11 +819,7 @@ func (dS *DispatcherService) ReplicatorSv1SetActions(args *engine.SetActionsArgs
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1SetActions, args, rpl)
 }
 
@@ -973,11 +837,7 @@ func (dS *DispatcherService) ReplicatorSv1SetRatingPlan(args *engine.RatingPlanW
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1SetRatingPlan, args, rpl)
 }
 
@@ -995,11 +855,7 @@ func (dS *DispatcherService) ReplicatorSv1SetRatingProfile(args *engine.RatingPr
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1SetRatingProfile, args, rpl)
 }
 
@@ -1017,11 +873,7 @@ func (dS *DispatcherService) ReplicatorSv1SetRouteProfile(args *engine.RouteProf
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1SetRouteProfile, args, rpl)
 }
 
@@ -1039,11 +891,7 @@ func (dS *DispatcherService) ReplicatorSv1SetAttributeProfile(args *engine.Attri
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1SetAttributeProfile, args, rpl)
 }
 
@@ -1061,11 +909,7 @@ func (dS *DispatcherService) ReplicatorSv1SetChargerProfile(args *engine.Charger
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1SetChargerProfile, args, rpl)
 }
 
@@ -1083,11 +927,7 @@ func (dS *DispatcherService) ReplicatorSv1SetDispatcherProfile(args *engine.Disp
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1SetDispatcherProfile, args, rpl)
 }
 
@@ -1105,11 +945,7 @@ func (dS *DispatcherService) ReplicatorSv1SetActionPlan(args *engine.SetActionPl
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1SetActionPlan, args, rpl)
 }
 
@@ -1127,11 +963,7 @@ func (dS *DispatcherService) ReplicatorSv1SetAccountActionPlans(args *engine.Set
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1SetAccountActionPlans, args, rpl)
 }
 
@@ -1149,11 +981,7 @@ func (dS *DispatcherService) ReplicatorSv1SetDispatcherHost(args *engine.Dispatc
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1SetDispatcherHost, args, rpl)
 }
 
@@ -1171,11 +999,7 @@ func (dS *DispatcherService) ReplicatorSv1RemoveThreshold(args *utils.TenantIDWi
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1RemoveThreshold, args, rpl)
 }
 
@@ -1193,11 +1017,7 @@ func (dS *DispatcherService) ReplicatorSv1RemoveDestination(args *utils.StringWi
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaReplicator, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1RemoveDestination, args, rpl)
 }
 
@@ -1215,11 +1035,7 @@ func (dS *DispatcherService) ReplicatorSv1SetLoadIDs(args *utils.LoadIDsWithArgD
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaReplicator, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1SetLoadIDs, args, rpl)
 }
 
@@ -1237,11 +1053,7 @@ func (dS *DispatcherService) ReplicatorSv1RemoveAccount(args *utils.StringWithAp
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaReplicator, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1RemoveAccount, args, rpl)
 }
 
@@ -1259,11 +1071,7 @@ func (dS *DispatcherService) ReplicatorSv1RemoveStatQueue(args *utils.TenantIDWi
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1RemoveStatQueue, args, rpl)
 }
 
@@ -1281,11 +1089,7 @@ func (dS *DispatcherService) ReplicatorSv1RemoveFilter(args *utils.TenantIDWithA
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1RemoveFilter, args, rpl)
 }
 
@@ -1303,11 +1107,7 @@ func (dS *DispatcherService) ReplicatorSv1RemoveThresholdProfile(args *utils.Ten
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1RemoveThresholdProfile, args, rpl)
 }
 
@@ -1325,11 +1125,7 @@ func (dS *DispatcherService) ReplicatorSv1RemoveStatQueueProfile(args *utils.Ten
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1RemoveStatQueueProfile, args, rpl)
 }
 
@@ -1347,11 +1143,7 @@ func (dS *DispatcherService) ReplicatorSv1RemoveTiming(args *utils.StringWithApi
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaReplicator, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1RemoveTiming, args, rpl)
 }
 
@@ -1369,11 +1161,7 @@ func (dS *DispatcherService) ReplicatorSv1RemoveResource(args *utils.TenantIDWit
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1RemoveResource, args, rpl)
 }
 
@@ -1391,11 +1179,7 @@ func (dS *DispatcherService) ReplicatorSv1RemoveResourceProfile(args *utils.Tena
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1RemoveResourceProfile, args, rpl)
 }
 
@@ -1413,11 +1197,7 @@ func (dS *DispatcherService) ReplicatorSv1RemoveActionTriggers(args *utils.Strin
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaReplicator, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1RemoveActionTriggers, args, rpl)
 }
 
@@ -1435,11 +1215,7 @@ func (dS *DispatcherService) ReplicatorSv1RemoveSharedGroup(args *utils.StringWi
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaReplicator, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1RemoveSharedGroup, args, rpl)
 }
 
@@ -1457,11 +1233,7 @@ func (dS *DispatcherService) ReplicatorSv1RemoveActions(args *utils.StringWithAp
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaReplicator, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1RemoveActions, args, rpl)
 }
 
@@ -1479,11 +1251,7 @@ func (dS *DispatcherService) ReplicatorSv1RemoveActionPlan(args *utils.StringWit
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaReplicator, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1RemoveActionPlan, args, rpl)
 }
 
@@ -1501,11 +1269,7 @@ func (dS *DispatcherService) ReplicatorSv1RemAccountActionPlans(args *engine.Rem
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaReplicator, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1RemAccountActionPlans, args, rpl)
 }
 
@@ -1523,11 +1287,7 @@ func (dS *DispatcherService) ReplicatorSv1RemoveRatingPlan(args *utils.StringWit
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaReplicator, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1RemoveRatingPlan, args, rpl)
 }
 
@@ -1545,11 +1305,7 @@ func (dS *DispatcherService) ReplicatorSv1RemoveRatingProfile(args *utils.String
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaReplicator, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.TenantArg.Tenant}, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1RemoveRatingProfile, args, rpl)
 }
 
@@ -1567,11 +1323,7 @@ func (dS *DispatcherService) ReplicatorSv1RemoveRouteProfile(args *utils.TenantI
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1RemoveRouteProfile, args, rpl)
 }
 
@@ -1589,11 +1341,7 @@ func (dS *DispatcherService) ReplicatorSv1RemoveAttributeProfile(args *utils.Ten
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1RemoveAttributeProfile, args, rpl)
 }
 
@@ -1611,11 +1359,7 @@ func (dS *DispatcherService) ReplicatorSv1RemoveChargerProfile(args *utils.Tenan
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1RemoveChargerProfile, args, rpl)
 }
 
@@ -1633,11 +1377,7 @@ func (dS *DispatcherService) ReplicatorSv1RemoveDispatcherProfile(args *utils.Te
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1RemoveDispatcherProfile, args, rpl)
 }
 
@@ -1655,10 +1395,6 @@ func (dS *DispatcherService) ReplicatorSv1RemoveDispatcherHost(args *utils.Tenan
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaReplicator, args.ArgDispatcher,
 		utils.ReplicatorSv1RemoveDispatcherHost, args, rpl)
 }
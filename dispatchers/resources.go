@@ -39,11 +39,7 @@ func (dS *DispatcherService) ResourceSv1Ping(args *utils.CGREventWithArgDispatch
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(args.CGREvent, utils.MetaResources, routeID,
+	return dS.Dispatch(args.CGREvent, utils.MetaResources, args.ArgDispatcher,
 		utils.ResourceSv1Ping, args, rpl)
 }
 
@@ -63,11 +59,7 @@ func (dS *DispatcherService) ResourceSv1GetResourcesForEvent(args utils.ArgRSv1R
 		}
 
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(args.CGREvent, utils.MetaResources, routeID,
+	return dS.Dispatch(args.CGREvent, utils.MetaResources, args.ArgDispatcher,
 		utils.ResourceSv1GetResourcesForEvent, args, reply)
 }
 
@@ -87,11 +79,7 @@ func (dS *DispatcherService) ResourceSv1AuthorizeResources(args utils.ArgRSv1Res
 		}
 
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(args.CGREvent, utils.MetaResources, routeID,
+	return dS.Dispatch(args.CGREvent, utils.MetaResources, args.ArgDispatcher,
 		utils.ResourceSv1AuthorizeResources, args, reply)
 }
 
@@ -111,11 +99,7 @@ func (dS *DispatcherService) ResourceSv1AllocateResources(args utils.ArgRSv1Reso
 		}
 
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(args.CGREvent, utils.MetaResources, routeID,
+	return dS.Dispatch(args.CGREvent, utils.MetaResources, args.ArgDispatcher,
 		utils.ResourceSv1AllocateResources, args, reply)
 }
 
@@ -135,11 +119,7 @@ func (dS *DispatcherService) ResourceSv1ReleaseResources(args utils.ArgRSv1Resou
 		}
 
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(args.CGREvent, utils.MetaResources, routeID,
+	return dS.Dispatch(args.CGREvent, utils.MetaResources, args.ArgDispatcher,
 		utils.ResourceSv1ReleaseResources, args, reply)
 }
 
@@ -157,9 +137,8 @@ func (dS *DispatcherService) ResourceSv1GetResource(args *utils.TenantIDWithArgD
 			return
 		}
 	}
-	routeID := args.ArgDispatcher.RouteID
 	return dS.Dispatch(&utils.CGREvent{
 		Tenant: tnt,
 		ID:     args.ID,
-	}, utils.MetaResources, routeID, utils.ResourceSv1GetResource, args, reply)
+	}, utils.MetaResources, args.ArgDispatcher, utils.ResourceSv1GetResource, args, reply)
 }
@@ -41,12 +41,8 @@ func (dS *DispatcherService) ResponderPing(args *utils.CGREventWithArgDispatcher
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
 	return dS.Dispatch(args.CGREvent, utils.MetaResponder,
-		routeID, utils.ResponderPing, args, reply)
+		args.ArgDispatcher, utils.ResponderPing, args, reply)
 }
 
 func (dS *DispatcherService) ResponderGetCost(args *engine.CallDescriptorWithArgDispatcher,
@@ -60,12 +56,8 @@ func (dS *DispatcherService) ResponderGetCost(args *engine.CallDescriptorWithArg
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
 	return dS.Dispatch(args.AsCGREvent(), utils.MetaResponder,
-		routeID, utils.ResponderGetCost, args, reply)
+		args.ArgDispatcher, utils.ResponderGetCost, args, reply)
 }
 
 func (dS *DispatcherService) ResponderDebit(args *engine.CallDescriptorWithArgDispatcher,
@@ -79,12 +71,8 @@ func (dS *DispatcherService) ResponderDebit(args *engine.CallDescriptorWithArgDi
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
 	return dS.Dispatch(args.AsCGREvent(), utils.MetaResponder,
-		routeID, utils.ResponderDebit, args, reply)
+		args.ArgDispatcher, utils.ResponderDebit, args, reply)
 }
 
 func (dS *DispatcherService) ResponderMaxDebit(args *engine.CallDescriptorWithArgDispatcher,
@@ -98,12 +86,8 @@ func (dS *DispatcherService) ResponderMaxDebit(args *engine.CallDescriptorWithAr
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
 	return dS.Dispatch(args.AsCGREvent(), utils.MetaResponder,
-		routeID, utils.ResponderMaxDebit, args, reply)
+		args.ArgDispatcher, utils.ResponderMaxDebit, args, reply)
 }
 
 func (dS *DispatcherService) ResponderRefundIncrements(args *engine.CallDescriptorWithArgDispatcher,
@@ -117,12 +101,8 @@ func (dS *DispatcherService) ResponderRefundIncrements(args *engine.CallDescript
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
 	return dS.Dispatch(args.AsCGREvent(), utils.MetaResponder,
-		routeID, utils.ResponderRefundIncrements, args, reply)
+		args.ArgDispatcher, utils.ResponderRefundIncrements, args, reply)
 }
 
 func (dS *DispatcherService) ResponderRefundRounding(args *engine.CallDescriptorWithArgDispatcher,
@@ -136,12 +116,8 @@ func (dS *DispatcherService) ResponderRefundRounding(args *engine.CallDescriptor
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
 	return dS.Dispatch(args.AsCGREvent(), utils.MetaResponder,
-		routeID, utils.ResponderRefundRounding, args, reply)
+		args.ArgDispatcher, utils.ResponderRefundRounding, args, reply)
 }
 
 func (dS *DispatcherService) ResponderGetMaxSessionTime(args *engine.CallDescriptorWithArgDispatcher,
@@ -155,12 +131,8 @@ func (dS *DispatcherService) ResponderGetMaxSessionTime(args *engine.CallDescrip
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
 	return dS.Dispatch(args.AsCGREvent(), utils.MetaResponder,
-		routeID, utils.ResponderGetMaxSessionTime, args, reply)
+		args.ArgDispatcher, utils.ResponderGetMaxSessionTime, args, reply)
 }
 
 func (dS *DispatcherService) ResponderShutdown(args *utils.TenantWithArgDispatcher,
@@ -175,10 +147,6 @@ func (dS *DispatcherService) ResponderShutdown(args *utils.TenantWithArgDispatch
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
 	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaResponder,
-		routeID, utils.ResponderShutdown, args, reply)
+		args.ArgDispatcher, utils.ResponderShutdown, args, reply)
 }
@@ -38,11 +38,7 @@ func (dS *DispatcherService) RouteSv1Ping(args *utils.CGREventWithArgDispatcher,
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(args.CGREvent, utils.MetaRoutes, routeID,
+	return dS.Dispatch(args.CGREvent, utils.MetaRoutes, args.ArgDispatcher,
 		utils.RouteSv1Ping, args, reply)
 }
 
@@ -59,11 +55,7 @@ func (dS *DispatcherService) RouteSv1GetRoutes(args *engine.ArgsGetRoutes,
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(args.CGREvent, utils.MetaRoutes, routeID,
+	return dS.Dispatch(args.CGREvent, utils.MetaRoutes, args.ArgDispatcher,
 		utils.RouteSv1GetRoutes, args, reply)
 }
 
@@ -80,10 +72,6 @@ func (dS *DispatcherService) RouteSv1GetRouteProfilesForEvent(args *utils.CGREve
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(args.CGREvent, utils.MetaRoutes, routeID,
+	return dS.Dispatch(args.CGREvent, utils.MetaRoutes, args.ArgDispatcher,
 		utils.RouteSv1GetRouteProfilesForEvent, args, reply)
 }
@@ -39,11 +39,7 @@ func (dS *DispatcherService) SchedulerSv1Ping(args *utils.CGREventWithArgDispatc
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(args.CGREvent, utils.MetaScheduler, routeID,
+	return dS.Dispatch(args.CGREvent, utils.MetaScheduler, args.ArgDispatcher,
 		utils.SchedulerSv1Ping, args, reply)
 }
 
@@ -59,11 +55,7 @@ func (dS *DispatcherService) SchedulerSv1Reload(args *utils.CGREventWithArgDispa
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(args.CGREvent, utils.MetaScheduler, routeID,
+	return dS.Dispatch(args.CGREvent, utils.MetaScheduler, args.ArgDispatcher,
 		utils.SchedulerSv1Reload, args, reply)
 }
 
@@ -79,11 +71,7 @@ func (dS *DispatcherService) SchedulerSv1ExecuteActions(args *utils.AttrsExecute
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaScheduler, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaScheduler, args.ArgDispatcher,
 		utils.SchedulerSv1ExecuteActions, args, reply)
 }
 
@@ -99,10 +87,6 @@ func (dS *DispatcherService) SchedulerSv1ExecuteActionPlans(args *utils.AttrsExe
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaScheduler, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: args.Tenant}, utils.MetaScheduler, args.ArgDispatcher,
 		utils.SchedulerSv1ExecuteActionPlans, args, reply)
 }
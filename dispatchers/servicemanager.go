@@ -41,11 +41,7 @@ func (dS *DispatcherService) ServiceManagerV1Ping(args *utils.CGREventWithArgDis
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(args.CGREvent, utils.MetaServiceManager, routeID,
+	return dS.Dispatch(args.CGREvent, utils.MetaServiceManager, args.ArgDispatcher,
 		utils.ServiceManagerV1Ping, args, reply)
 }
 
@@ -64,11 +60,7 @@ func (dS *DispatcherService) ServiceManagerV1StartService(args ArgStartServiceWi
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaServiceManager, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaServiceManager, args.ArgDispatcher,
 		utils.ServiceManagerV1StartService, args, reply)
 }
 
@@ -87,11 +79,7 @@ func (dS *DispatcherService) ServiceManagerV1StopService(args ArgStartServiceWit
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaServiceManager, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaServiceManager, args.ArgDispatcher,
 		utils.ServiceManagerV1StopService, args, reply)
 }
 
@@ -110,10 +98,6 @@ func (dS *DispatcherService) ServiceManagerV1ServiceStatus(args ArgStartServiceW
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaServiceManager, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaServiceManager, args.ArgDispatcher,
 		utils.ServiceManagerV1ServiceStatus, args, reply)
 }
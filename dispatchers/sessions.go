@@ -37,11 +37,7 @@ func (dS *DispatcherService) SessionSv1Ping(args *utils.CGREventWithArgDispatche
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(args.CGREvent, utils.MetaSessionS, routeID,
+	return dS.Dispatch(args.CGREvent, utils.MetaSessionS, args.ArgDispatcher,
 		utils.SessionSv1Ping, args, reply)
 }
 
@@ -58,11 +54,7 @@ func (dS *DispatcherService) SessionSv1AuthorizeEvent(args *sessions.V1Authorize
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(args.CGREvent, utils.MetaSessionS, routeID,
+	return dS.Dispatch(args.CGREvent, utils.MetaSessionS, args.ArgDispatcher,
 		utils.SessionSv1AuthorizeEvent, args, reply)
 }
 
@@ -79,11 +71,7 @@ func (dS *DispatcherService) SessionSv1AuthorizeEventWithDigest(args *sessions.V
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(args.CGREvent, utils.MetaSessionS, routeID,
+	return dS.Dispatch(args.CGREvent, utils.MetaSessionS, args.ArgDispatcher,
 		utils.SessionSv1AuthorizeEventWithDigest, args, reply)
 }
 
@@ -100,11 +88,7 @@ func (dS *DispatcherService) SessionSv1InitiateSession(args *sessions.V1InitSess
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(args.CGREvent, utils.MetaSessionS, routeID,
+	return dS.Dispatch(args.CGREvent, utils.MetaSessionS, args.ArgDispatcher,
 		utils.SessionSv1InitiateSession, args, reply)
 }
 
@@ -121,11 +105,7 @@ func (dS *DispatcherService) SessionSv1InitiateSessionWithDigest(args *sessions.
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(args.CGREvent, utils.MetaSessionS, routeID,
+	return dS.Dispatch(args.CGREvent, utils.MetaSessionS, args.ArgDispatcher,
 		utils.SessionSv1InitiateSessionWithDigest, args, reply)
 }
 
@@ -142,11 +122,7 @@ func (dS *DispatcherService) SessionSv1UpdateSession(args *sessions.V1UpdateSess
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(args.CGREvent, utils.MetaSessionS, routeID,
+	return dS.Dispatch(args.CGREvent, utils.MetaSessionS, args.ArgDispatcher,
 		utils.SessionSv1UpdateSession, args, reply)
 }
 
@@ -165,11 +141,7 @@ func (dS *DispatcherService) SessionSv1SyncSessions(args *utils.TenantWithArgDis
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaSessionS, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaSessionS, args.ArgDispatcher,
 		utils.SessionSv1SyncSessions, args, reply)
 }
 
@@ -186,11 +158,7 @@ func (dS *DispatcherService) SessionSv1TerminateSession(args *sessions.V1Termina
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(args.CGREvent, utils.MetaSessionS, routeID,
+	return dS.Dispatch(args.CGREvent, utils.MetaSessionS, args.ArgDispatcher,
 		utils.SessionSv1TerminateSession, args, reply)
 }
 
@@ -207,11 +175,7 @@ func (dS *DispatcherService) SessionSv1ProcessCDR(args *utils.CGREventWithArgDis
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(args.CGREvent, utils.MetaSessionS, routeID,
+	return dS.Dispatch(args.CGREvent, utils.MetaSessionS, args.ArgDispatcher,
 		utils.SessionSv1ProcessCDR, args, reply)
 }
 
@@ -228,11 +192,7 @@ func (dS *DispatcherService) SessionSv1ProcessMessage(args *sessions.V1ProcessMe
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(args.CGREvent, utils.MetaSessionS, routeID,
+	return dS.Dispatch(args.CGREvent, utils.MetaSessionS, args.ArgDispatcher,
 		utils.SessionSv1ProcessMessage, args, reply)
 }
 
@@ -249,11 +209,7 @@ func (dS *DispatcherService) SessionSv1ProcessEvent(args *sessions.V1ProcessEven
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(args.CGREvent, utils.MetaSessionS, routeID,
+	return dS.Dispatch(args.CGREvent, utils.MetaSessionS, args.ArgDispatcher,
 		utils.SessionSv1ProcessEvent, args, reply)
 }
 
@@ -270,11 +226,7 @@ func (dS *DispatcherService) SessionSv1GetCost(args *sessions.V1ProcessEventArgs
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(args.CGREvent, utils.MetaSessionS, routeID,
+	return dS.Dispatch(args.CGREvent, utils.MetaSessionS, args.ArgDispatcher,
 		utils.SessionSv1GetCost, args, reply)
 }
 
@@ -293,11 +245,7 @@ func (dS *DispatcherService) SessionSv1GetActiveSessions(args *utils.SessionFilt
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaSessionS, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaSessionS, args.ArgDispatcher,
 		utils.SessionSv1GetActiveSessions, args, reply)
 }
 
@@ -316,11 +264,7 @@ func (dS *DispatcherService) SessionSv1GetActiveSessionsCount(args *utils.Sessio
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaSessionS, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaSessionS, args.ArgDispatcher,
 		utils.SessionSv1GetActiveSessionsCount, args, reply)
 }
 
@@ -339,11 +283,7 @@ func (dS *DispatcherService) SessionSv1ForceDisconnect(args *utils.SessionFilter
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaSessionS, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaSessionS, args.ArgDispatcher,
 		utils.SessionSv1ForceDisconnect, args, reply)
 }
 
@@ -362,11 +302,7 @@ func (dS *DispatcherService) SessionSv1GetPassiveSessions(args *utils.SessionFil
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaSessionS, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaSessionS, args.ArgDispatcher,
 		utils.SessionSv1GetPassiveSessions, args, reply)
 }
 
@@ -385,11 +321,7 @@ func (dS *DispatcherService) SessionSv1GetPassiveSessionsCount(args *utils.Sessi
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaSessionS, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaSessionS, args.ArgDispatcher,
 		utils.SessionSv1GetPassiveSessionsCount, args, reply)
 }
 
@@ -408,11 +340,7 @@ func (dS *DispatcherService) SessionSv1ReplicateSessions(args ArgsReplicateSessi
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaSessionS, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaSessionS, args.ArgDispatcher,
 		utils.SessionSv1ReplicateSessions, args, reply)
 }
 
@@ -431,11 +359,7 @@ func (dS *DispatcherService) SessionSv1SetPassiveSession(args *sessions.Session,
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaSessionS, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaSessionS, args.ArgDispatcher,
 		utils.SessionSv1SetPassiveSession, args, reply)
 }
 
@@ -453,11 +377,7 @@ func (dS *DispatcherService) SessionSv1ActivateSessions(args *utils.SessionIDsWi
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaSessionS, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaSessionS, args.ArgDispatcher,
 		utils.SessionSv1ActivateSessions, args, reply)
 }
 
@@ -475,11 +395,7 @@ func (dS *DispatcherService) SessionSv1DeactivateSessions(args *utils.SessionIDs
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaSessionS, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaSessionS, args.ArgDispatcher,
 		utils.SessionSv1DeactivateSessions, args, reply)
 }
 
@@ -494,11 +410,7 @@ func (dS *DispatcherService) SessionSv1STIRAuthenticate(args *sessions.V1STIRAut
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaSessionS, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaSessionS, args.ArgDispatcher,
 		utils.SessionSv1STIRAuthenticate, args, reply)
 }
 
@@ -513,10 +425,6 @@ func (dS *DispatcherService) SessionSv1STIRIdentity(args *sessions.V1STIRIdentit
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaSessionS, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaSessionS, args.ArgDispatcher,
 		utils.SessionSv1STIRIdentity, args, reply)
 }
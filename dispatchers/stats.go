@@ -40,11 +40,7 @@ func (dS *DispatcherService) StatSv1Ping(args *utils.CGREventWithArgDispatcher,
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(args.CGREvent, utils.MetaStats, routeID,
+	return dS.Dispatch(args.CGREvent, utils.MetaStats, args.ArgDispatcher,
 		utils.StatSv1Ping, args, reply)
 }
 
@@ -61,11 +57,7 @@ func (dS *DispatcherService) StatSv1GetStatQueuesForEvent(args *engine.StatsArgs
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(args.CGREvent, utils.MetaStats, routeID,
+	return dS.Dispatch(args.CGREvent, utils.MetaStats, args.ArgDispatcher,
 		utils.StatSv1GetStatQueuesForEvent, args, reply)
 }
 
@@ -82,14 +74,10 @@ func (dS *DispatcherService) StatSv1GetQueueStringMetrics(args *utils.TenantIDWi
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
 	return dS.Dispatch(&utils.CGREvent{
 		Tenant: args.Tenant,
 		ID:     args.ID,
-	}, utils.MetaStats, routeID, utils.StatSv1GetQueueStringMetrics,
+	}, utils.MetaStats, args.ArgDispatcher, utils.StatSv1GetQueueStringMetrics,
 		args, reply)
 }
 
@@ -106,11 +94,7 @@ func (dS *DispatcherService) StatSv1ProcessEvent(args *engine.StatsArgsProcessEv
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(args.CGREvent, utils.MetaStats, routeID,
+	return dS.Dispatch(args.CGREvent, utils.MetaStats, args.ArgDispatcher,
 		utils.StatSv1ProcessEvent, args, reply)
 }
 
@@ -126,14 +110,10 @@ func (dS *DispatcherService) StatSv1GetQueueFloatMetrics(args *utils.TenantIDWit
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
 	return dS.Dispatch(&utils.CGREvent{
 		Tenant: args.Tenant,
 		ID:     args.ID,
-	}, utils.MetaStats, routeID, utils.StatSv1GetQueueFloatMetrics,
+	}, utils.MetaStats, args.ArgDispatcher, utils.StatSv1GetQueueFloatMetrics,
 		args, reply)
 }
 
@@ -152,11 +132,7 @@ func (dS *DispatcherService) StatSv1GetQueueIDs(args *utils.TenantWithArgDispatc
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
 	return dS.Dispatch(&utils.CGREvent{Tenant: tnt},
-		utils.MetaStats, routeID, utils.StatSv1GetQueueIDs,
+		utils.MetaStats, args.ArgDispatcher, utils.StatSv1GetQueueIDs,
 		args, reply)
 }
@@ -40,11 +40,7 @@ func (dS *DispatcherService) ThresholdSv1Ping(args *utils.CGREventWithArgDispatc
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(args.CGREvent, utils.MetaThresholds, routeID,
+	return dS.Dispatch(args.CGREvent, utils.MetaThresholds, args.ArgDispatcher,
 		utils.ThresholdSv1Ping, args, reply)
 }
 
@@ -61,11 +57,7 @@ func (dS *DispatcherService) ThresholdSv1GetThresholdsForEvent(args *engine.Args
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(args.CGREvent, utils.MetaThresholds, routeID,
+	return dS.Dispatch(args.CGREvent, utils.MetaThresholds, args.ArgDispatcher,
 		utils.ThresholdSv1GetThresholdsForEvent, args, t)
 }
 
@@ -82,11 +74,7 @@ func (dS *DispatcherService) ThresholdSv1ProcessEvent(args *engine.ArgsProcessEv
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(args.CGREvent, utils.MetaThresholds, routeID,
+	return dS.Dispatch(args.CGREvent, utils.MetaThresholds, args.ArgDispatcher,
 		utils.ThresholdSv1ProcessEvent, args, tIDs)
 }
 
@@ -104,11 +92,7 @@ func (dS *DispatcherService) ThresholdSv1GetThresholdIDs(args *utils.TenantWithA
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
-	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaThresholds, routeID,
+	return dS.Dispatch(&utils.CGREvent{Tenant: tnt}, utils.MetaThresholds, args.ArgDispatcher,
 		utils.ThresholdSv1GetThresholdIDs, args, tIDs)
 }
 
@@ -126,12 +110,8 @@ func (dS *DispatcherService) ThresholdSv1GetThreshold(args *utils.TenantIDWithAr
 			return
 		}
 	}
-	var routeID *string
-	if args.ArgDispatcher != nil {
-		routeID = args.ArgDispatcher.RouteID
-	}
 	return dS.Dispatch(&utils.CGREvent{
 		Tenant: tnt,
 		ID:     args.ID,
-	}, utils.MetaThresholds, routeID, utils.ThresholdSv1GetThreshold, args, th)
+	}, utils.MetaThresholds, args.ArgDispatcher, utils.ThresholdSv1GetThreshold, args, th)
 }
@@ -35,6 +35,8 @@ type DispatcherHostProfile struct {
 	Blocker   bool                   // no connection after this one
 }
 
+// Clone returns a deep copy of the host profile, nested Params values included,
+// so the dispatchers can keep their own hosts without sharing them with the profile
 func (dC *DispatcherHostProfile) Clone() (cln *DispatcherHostProfile) {
 	cln = &DispatcherHostProfile{
 		ID:      dC.ID,
@@ -50,17 +52,97 @@ func (dC *DispatcherHostProfile) Clone() (cln *DispatcherHostProfile) {
 	if dC.Params != nil {
 		cln.Params = make(map[string]interface{})
 		for k, v := range dC.Params {
-			cln.Params[k] = v
+			cln.Params[k] = cloneHostParam(v)
 		}
 	}
 	return
 }
 
+// cloneHostParam copies the mutable values a host param can hold
+func cloneHostParam(param interface{}) interface{} {
+	switch p := param.(type) {
+	case map[string]interface{}:
+		cln := make(map[string]interface{}, len(p))
+		for k, v := range p {
+			cln[k] = cloneHostParam(v)
+		}
+		return cln
+	case []interface{}:
+		cln := make([]interface{}, len(p))
+		for i, v := range p {
+			cln[i] = cloneHostParam(v)
+		}
+		return cln
+	case map[string]string:
+		cln := make(map[string]string, len(p))
+		for k, v := range p {
+			cln[k] = v
+		}
+		return cln
+	case []string:
+		cln := make([]string, len(p))
+		copy(cln, p)
+		return cln
+	default:
+		return param
+	}
+}
+
+// Priority returns the tier of the host out of its *priority param, 0 if not configured
+// higher tiers are used only if the lower ones are not available
+func (dC *DispatcherHostProfile) Priority() (priority int, err error) {
+	prio, has := dC.Params[utils.MetaPriority]
+	if !has {
+		return
+	}
+	var prio64 int64
+	if prio64, err = utils.IfaceAsTInt64(prio); err != nil {
+		return
+	}
+	return int(prio64), nil
+}
+
+// HasTags returns true if the host is labeled with all the required tags
+// the tags are the host Params, compared as strings
+func (dC *DispatcherHostProfile) HasTags(required map[string]string) bool {
+	for k, v := range required {
+		if tag, has := dC.Params[k]; !has || utils.IfaceAsString(tag) != v {
+			return false
+		}
+	}
+	return true
+}
+
 type DispatcherHostProfiles []*DispatcherHostProfile
 
-// Sort is part of sort interface, sort based on Weight
+// Sort is part of sort interface, sort based on Priority tier and Weight inside the tier
+// an invalid *priority is considered 0, the dispatchers refuse such hosts on validation
 func (dHPrfls DispatcherHostProfiles) Sort() {
-	sort.Slice(dHPrfls, func(i, j int) bool { return dHPrfls[i].Weight > dHPrfls[j].Weight })
+	sort.Slice(dHPrfls, func(i, j int) bool {
+		iPrio, _ := dHPrfls[i].Priority()
+		jPrio, _ := dHPrfls[j].Priority()
+		if iPrio != jPrio {
+			return iPrio < jPrio
+		}
+		return dHPrfls[i].Weight > dHPrfls[j].Weight
+	})
+}
+
+// Tiers splits the sorted hosts based on Priority, lowest tier first
+// the tiers share the underlying array so reordering one tier reorders the hosts
+func (dHPrfls DispatcherHostProfiles) Tiers() (tiers []DispatcherHostProfiles) {
+	if len(dHPrfls) == 0 {
+		return
+	}
+	var start int
+	startPrio, _ := dHPrfls[0].Priority()
+	for i := 1; i < len(dHPrfls); i++ {
+		if prio, _ := dHPrfls[i].Priority(); prio != startPrio {
+			tiers = append(tiers, dHPrfls[start:i])
+			start, startPrio = i, prio
+		}
+	}
+	return append(tiers, dHPrfls[start:])
 }
 
 // ReorderFromIndex will consider idx as starting point for the reordered slice
@@ -84,6 +166,15 @@ func (dHPrfls DispatcherHostProfiles) Shuffle() {
 	return
 }
 
+// ShuffleWithSeed will mix the connections in place, always in the same way for the same seed
+func (dHPrfls DispatcherHostProfiles) ShuffleWithSeed(seed int64) {
+	rand.New(rand.NewSource(seed)).Shuffle(len(dHPrfls), func(i, j int) {
+		dHPrfls[i], dHPrfls[j] = dHPrfls[j], dHPrfls[i]
+	})
+	return
+}
+
+// Clone returns a deep copy of the hosts, see DispatcherHostProfile.Clone
 func (dHPrfls DispatcherHostProfiles) Clone() (cln DispatcherHostProfiles) {
 	cln = make(DispatcherHostProfiles, len(dHPrfls))
 	for i, dHPrfl := range dHPrfls {
@@ -113,6 +204,38 @@ type DispatcherProfile struct {
 	Hosts              DispatcherHostProfiles // dispatch to these connections
 }
 
+// Clone returns a deep copy of the profile
+func (dP *DispatcherProfile) Clone() (cln *DispatcherProfile) {
+	cln = &DispatcherProfile{
+		Tenant:   dP.Tenant,
+		ID:       dP.ID,
+		Strategy: dP.Strategy,
+		Weight:   dP.Weight,
+	}
+	if dP.Subsystems != nil {
+		cln.Subsystems = make([]string, len(dP.Subsystems))
+		copy(cln.Subsystems, dP.Subsystems)
+	}
+	if dP.FilterIDs != nil {
+		cln.FilterIDs = make([]string, len(dP.FilterIDs))
+		copy(cln.FilterIDs, dP.FilterIDs)
+	}
+	if dP.ActivationInterval != nil {
+		aI := *dP.ActivationInterval
+		cln.ActivationInterval = &aI
+	}
+	if dP.StrategyParams != nil {
+		cln.StrategyParams = make(map[string]interface{})
+		for k, v := range dP.StrategyParams {
+			cln.StrategyParams[k] = cloneHostParam(v)
+		}
+	}
+	if dP.Hosts != nil {
+		cln.Hosts = dP.Hosts.Clone()
+	}
+	return
+}
+
 // DispatcherProfileWithArgDispatcher is used in replicatorV1 for dispatcher
 type DispatcherProfileWithArgDispatcher struct {
 	*DispatcherProfile
@@ -20,6 +20,7 @@ package engine
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/cgrates/cgrates/utils"
 )
@@ -252,3 +253,171 @@ func TestDispatcherHostCall(t *testing.T) {
 		t.Errorf("Expected: %s , received: %s", utils.ToJSON(etRPC), utils.ToJSON(tRPC))
 	}
 }
+
+func TestDispatcherHostProfileHasTags(t *testing.T) {
+	dConn := &DispatcherHostProfile{
+		ID:     "DSP_1",
+		Params: map[string]interface{}{"version": "1.0", "region": "eu", "gpu": true, utils.MetaRatio: 2.},
+	}
+	if !dConn.HasTags(nil) {
+		t.Error("expecting empty requirements to match")
+	}
+	if !dConn.HasTags(map[string]string{"region": "eu"}) {
+		t.Error("expecting region to match")
+	}
+	if !dConn.HasTags(map[string]string{"region": "eu", "version": "1.0"}) {
+		t.Error("expecting region and version to match")
+	}
+	if dConn.HasTags(map[string]string{"region": "us"}) {
+		t.Error("not expecting different region to match")
+	}
+	if !dConn.HasTags(map[string]string{"gpu": "true", utils.MetaRatio: "2"}) {
+		t.Error("expecting the params to match as strings")
+	}
+	if dConn.HasTags(map[string]string{"region": "eu", "zone": "a"}) {
+		t.Error("not expecting missing tag to match")
+	}
+}
+
+func TestDispatcherHostProfilesShuffleWithSeed(t *testing.T) {
+	dConns := DispatcherHostProfiles{
+		{ID: "DSP_1", Weight: 30},
+		{ID: "DSP_2", Weight: 20},
+		{ID: "DSP_3", Weight: 10},
+		{ID: "DSP_4", Weight: 5},
+	}
+	d2Conns := dConns.Clone()
+	dConns.ShuffleWithSeed(10)
+	d2Conns.ShuffleWithSeed(10)
+	if !reflect.DeepEqual(dConns, d2Conns) {
+		t.Errorf("expecting: %+v, received: %+v", utils.ToJSON(dConns), utils.ToJSON(d2Conns))
+	}
+}
+
+func TestDispatcherHostProfilesSortPriority(t *testing.T) {
+	dConns := DispatcherHostProfiles{
+		{ID: "DSP_1", Weight: 30, Params: map[string]interface{}{utils.MetaPriority: 1}},
+		{ID: "DSP_2", Weight: 20},
+		{ID: "DSP_3", Weight: 10, Params: map[string]interface{}{utils.MetaPriority: 2}},
+		{ID: "DSP_4", Weight: 40},
+		{ID: "DSP_5", Weight: 50, Params: map[string]interface{}{utils.MetaPriority: 1}},
+	}
+	eConnIDs := []string{"DSP_4", "DSP_2", "DSP_5", "DSP_1", "DSP_3"}
+	if dConns.Sort(); !reflect.DeepEqual(eConnIDs, dConns.HostIDs()) {
+		t.Errorf("expecting: %+v, received: %+v", eConnIDs, dConns.HostIDs())
+	}
+}
+
+func TestDispatcherHostProfilePriority(t *testing.T) {
+	for _, tc := range []struct {
+		params map[string]interface{}
+		exp    int
+	}{
+		{nil, 0},
+		{map[string]interface{}{utils.MetaRatio: "2"}, 0},
+		{map[string]interface{}{utils.MetaPriority: 2}, 2},
+		{map[string]interface{}{utils.MetaPriority: 2.}, 2},  // from JSON
+		{map[string]interface{}{utils.MetaPriority: "2"}, 2}, // from TP
+	} {
+		dC := &DispatcherHostProfile{ID: "DSP_1", Params: tc.params}
+		if prio, err := dC.Priority(); err != nil {
+			t.Error(err)
+		} else if prio != tc.exp {
+			t.Errorf("for %+v expecting: %d, received: %d", tc.params, tc.exp, prio)
+		}
+	}
+	dC := &DispatcherHostProfile{ID: "DSP_1", Params: map[string]interface{}{utils.MetaPriority: "high"}}
+	if _, err := dC.Priority(); err == nil {
+		t.Error("expecting error for invalid priority")
+	}
+}
+
+func TestDispatcherHostProfilesTiers(t *testing.T) {
+	dConns := DispatcherHostProfiles{
+		{ID: "DSP_4", Weight: 40},
+		{ID: "DSP_2", Weight: 20},
+		{ID: "DSP_5", Weight: 50, Params: map[string]interface{}{utils.MetaPriority: 1}},
+		{ID: "DSP_1", Weight: 30, Params: map[string]interface{}{utils.MetaPriority: 1}},
+		{ID: "DSP_3", Weight: 10, Params: map[string]interface{}{utils.MetaPriority: 2}},
+	}
+	eTiers := [][]string{{"DSP_4", "DSP_2"}, {"DSP_5", "DSP_1"}, {"DSP_3"}}
+	tiers := dConns.Tiers()
+	rcvTiers := make([][]string, len(tiers))
+	for i, tier := range tiers {
+		rcvTiers[i] = tier.HostIDs()
+	}
+	if !reflect.DeepEqual(eTiers, rcvTiers) {
+		t.Errorf("expecting: %+v, received: %+v", eTiers, rcvTiers)
+	}
+	tiers[1].ReorderFromIndex(1)
+	eConnIDs := []string{"DSP_4", "DSP_2", "DSP_1", "DSP_5", "DSP_3"}
+	if !reflect.DeepEqual(eConnIDs, dConns.HostIDs()) {
+		t.Errorf("expecting: %+v, received: %+v", eConnIDs, dConns.HostIDs())
+	}
+	if tiers := (DispatcherHostProfiles{}).Tiers(); len(tiers) != 0 {
+		t.Errorf("expecting no tiers, received: %+v", tiers)
+	}
+}
+
+func TestDispatcherHostProfileCloneParams(t *testing.T) {
+	dConn := &DispatcherHostProfile{
+		ID: "DSP_1",
+		Params: map[string]interface{}{
+			utils.MetaRatio: "1",
+			"Nested":        map[string]interface{}{"Key": []interface{}{"Val1"}},
+			"Strings":       []string{"Str1"},
+		},
+	}
+	eConn := &DispatcherHostProfile{
+		ID: "DSP_1",
+		Params: map[string]interface{}{
+			utils.MetaRatio: "1",
+			"Nested":        map[string]interface{}{"Key": []interface{}{"Val1"}},
+			"Strings":       []string{"Str1"},
+		},
+	}
+	d2Conn := dConn.Clone()
+	if !reflect.DeepEqual(eConn, d2Conn) {
+		t.Errorf("expecting: %+v, received: %+v", utils.ToJSON(eConn), utils.ToJSON(d2Conn))
+	}
+	d2Conn.Params[utils.MetaRatio] = "2"
+	d2Conn.Params["Nested"].(map[string]interface{})["Key"].([]interface{})[0] = "Val2"
+	d2Conn.Params["Strings"].([]string)[0] = "Str2"
+	if !reflect.DeepEqual(eConn, dConn) {
+		t.Errorf("expecting: %+v, received: %+v", utils.ToJSON(eConn), utils.ToJSON(dConn))
+	}
+}
+
+func TestDispatcherProfileClone(t *testing.T) {
+	dProf := &DispatcherProfile{
+		Tenant:     "cgrates.org",
+		ID:         "DSP_1",
+		Subsystems: []string{utils.META_ANY},
+		FilterIDs:  []string{"*string:~*req.Account:1001"},
+		ActivationInterval: &utils.ActivationInterval{
+			ActivationTime: time.Date(2020, 4, 18, 14, 25, 0, 0, time.UTC),
+		},
+		Strategy:       utils.MetaWeight,
+		StrategyParams: map[string]interface{}{utils.MetaTieBreak: utils.MetaID},
+		Weight:         20,
+		Hosts: DispatcherHostProfiles{
+			{ID: "DSP_1", Weight: 30, Params: map[string]interface{}{"region": "eu"}},
+		},
+	}
+	cln := dProf.Clone()
+	if !reflect.DeepEqual(dProf, cln) {
+		t.Errorf("expecting: %+v, received: %+v", utils.ToJSON(dProf), utils.ToJSON(cln))
+	}
+	cln.Subsystems[0] = utils.MetaAttributes
+	cln.FilterIDs[0] = "*string:~*req.Account:1002"
+	cln.ActivationInterval.ActivationTime = time.Time{}
+	cln.StrategyParams[utils.MetaTieBreak] = utils.MetaRandom
+	cln.Hosts[0].Params["region"] = "us"
+	if dProf.Subsystems[0] != utils.META_ANY ||
+		dProf.FilterIDs[0] != "*string:~*req.Account:1001" ||
+		dProf.ActivationInterval.ActivationTime.IsZero() ||
+		dProf.StrategyParams[utils.MetaTieBreak] != utils.MetaID ||
+		dProf.Hosts[0].Params["region"] != "eu" {
+		t.Errorf("clone changes reached the profile: %+v", utils.ToJSON(dProf))
+	}
+}
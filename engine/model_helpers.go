@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -2808,8 +2809,13 @@ func APItoDispatcherProfile(tpDPP *utils.TPDispatcherProfile, timezone string) (
 		dpp.Subsystems[i] = sub
 	}
 	for i, param := range tpDPP.StrategyParams {
-		if param != "" {
-			dpp.StrategyParams[strconv.Itoa(i)] = param
+		if param == "" {
+			continue
+		}
+		if p := strings.SplitN(utils.IfaceAsString(param), utils.CONCATENATED_KEY_SEP, 2); len(p) == 1 {
+			dpp.StrategyParams[strconv.Itoa(i)] = p[0]
+		} else {
+			dpp.StrategyParams[p[0]] = p[1]
 		}
 	}
 	for i, conn := range tpDPP.Hosts {
@@ -2862,12 +2868,28 @@ func DispatcherProfileToAPI(dpp *DispatcherProfile) (tpDPP *utils.TPDispatcherPr
 	for i, sub := range dpp.Subsystems {
 		tpDPP.Subsystems[i] = sub
 	}
-	for key, val := range dpp.StrategyParams {
-		// here we expect that the key to be an integer because
-		// according to APItoDispatcherProfile when we convert from TP to obj we use index as key
-		// so we can ignore error
-		idx, _ := strconv.Atoi(key)
-		tpDPP.StrategyParams[idx] = val
+	// the params without name keep their index as key, see APItoDispatcherProfile,
+	// they are exported first in their order followed by the named ones as key:value, sorted by key
+	idxKeys := make([]string, 0, len(dpp.StrategyParams))
+	var keys []string
+	for key := range dpp.StrategyParams {
+		if _, err := strconv.Atoi(key); err == nil {
+			idxKeys = append(idxKeys, key)
+		} else {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(idxKeys, func(i, j int) bool {
+		iIdx, _ := strconv.Atoi(idxKeys[i])
+		jIdx, _ := strconv.Atoi(idxKeys[j])
+		return iIdx < jIdx
+	})
+	sort.Strings(keys)
+	for i, key := range idxKeys {
+		tpDPP.StrategyParams[i] = dpp.StrategyParams[key]
+	}
+	for i, key := range keys {
+		tpDPP.StrategyParams[len(idxKeys)+i] = utils.ConcatenatedKey(key, utils.IfaceAsString(dpp.StrategyParams[key]))
 	}
 	for i, host := range dpp.Hosts {
 		tpDPP.Hosts[i] = &utils.TPDispatcherHostProfile{
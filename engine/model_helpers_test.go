@@ -2048,6 +2048,68 @@ func TestDispatcherProfileToAPI(t *testing.T) {
 	}
 }
 
+func TestDispatcherProfileStrategyParamsRoundTrip(t *testing.T) {
+	tpDPP := &utils.TPDispatcherProfile{
+		Tenant:             "cgrates.org",
+		ID:                 "Dsp",
+		Subsystems:         []string{"*any"},
+		FilterIDs:          []string{},
+		Strategy:           utils.MetaBroadcast,
+		ActivationInterval: &utils.TPActivationInterval{},
+		StrategyParams:     []interface{}{"param0", "*quorum:2", "*kill_switch:DSP_1", "*affinity_field:OriginID"},
+		Weight:             20,
+		Hosts: []*utils.TPDispatcherHostProfile{
+			&utils.TPDispatcherHostProfile{
+				ID:        "C1",
+				FilterIDs: []string{},
+				Weight:    10,
+				Params:    []interface{}{},
+			},
+		},
+	}
+	expParams := map[string]interface{}{
+		"0":                     "param0",
+		utils.MetaQuorum:        "2",
+		utils.MetaKillSwitch:    "DSP_1",
+		utils.MetaAffinityField: "OriginID",
+	}
+	dpp, err := APItoDispatcherProfile(tpDPP, "UTC")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(expParams, dpp.StrategyParams) {
+		t.Errorf("Expecting : %+v, received: %+v", expParams, dpp.StrategyParams)
+	}
+	// the unnamed params first, then the named ones sorted by key
+	expTP := []interface{}{"param0", "*affinity_field:OriginID", "*kill_switch:DSP_1", "*quorum:2"}
+	rcv := DispatcherProfileToAPI(dpp)
+	if !reflect.DeepEqual(expTP, rcv.StrategyParams) {
+		t.Errorf("Expecting : %+v, received: %+v", expTP, rcv.StrategyParams)
+	}
+	if dpp2, err := APItoDispatcherProfile(rcv, "UTC"); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(dpp, dpp2) {
+		t.Errorf("Expecting : %+v, received: %+v", utils.ToJSON(dpp), utils.ToJSON(dpp2))
+	}
+	// a profile set over the API with named params only keeps all of them on export
+	dpp.StrategyParams = map[string]interface{}{utils.MetaQuorum: 2, utils.MetaTieBreak: utils.MetaRandom}
+	expTP = []interface{}{"*quorum:2", "*tie_break:*random"}
+	if rcv := DispatcherProfileToAPI(dpp); !reflect.DeepEqual(expTP, rcv.StrategyParams) {
+		t.Errorf("Expecting : %+v, received: %+v", expTP, rcv.StrategyParams)
+	}
+	mdls := APItoModelTPDispatcherProfile(tpDPP)
+	if len(mdls) != 1 {
+		t.Fatalf("Expecting one model, received: %+v", utils.ToJSON(mdls))
+	}
+	if exp := "param0;*quorum:2;*kill_switch:DSP_1;*affinity_field:OriginID"; mdls[0].StrategyParameters != exp {
+		t.Errorf("Expecting : %q, received: %q", exp, mdls[0].StrategyParameters)
+	}
+	if tps := mdls.AsTPDispatcherProfiles(); len(tps) != 1 ||
+		!reflect.DeepEqual(tpDPP.StrategyParams, tps[0].StrategyParams) {
+		t.Errorf("Expecting : %+v, received: %+v", tpDPP.StrategyParams, utils.ToJSON(tps))
+	}
+}
+
 func TestAPItoModelTPDispatcher(t *testing.T) {
 	tpDPP := &utils.TPDispatcherProfile{
 		TPid:       "TP1",
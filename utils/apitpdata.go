@@ -1345,8 +1345,9 @@ type SessionFilter struct {
 }
 
 type ArgDispatcher struct {
-	APIKey  *string
-	RouteID *string
+	APIKey   *string
+	RouteID  *string
+	Strategy *string // overrides the strategy of the matched dispatcher profile for this call only
 }
 
 type RatingPlanCostArg struct {
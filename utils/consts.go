@@ -924,8 +924,11 @@ const (
 	MetaFirst          = "*first"
 	MetaRandom         = "*random"
 	MetaBroadcast      = "*broadcast"
+	MetaBroadcastSync  = "*broadcast_sync"
+	MetaBroadcastAsync = "*broadcast_async"
 	MetaRoundRobin     = "*round_robin"
 	MetaRatio          = "*ratio"
+	MetaPriority       = "*priority"
 	ThresholdSv1       = "ThresholdSv1"
 	StatSv1            = "StatSv1"
 	ResourceSv1        = "ResourceSv1"
@@ -939,6 +942,23 @@ const (
 	ArgDispatcherField = "ArgDispatcher"
 )
 
+// Dispatcher strategy params
+const (
+	MetaQuorum           = "*quorum"
+	MetaEventIDSeed      = "*event_id_seed"
+	MetaSelectionLatency = "*selection_latency"
+	MetaTieBreak         = "*tie_break"
+	MetaID               = "*id"
+	MetaAntiAffinity     = "*anti_affinity"
+	MetaPreserveOrder    = "*preserve_order"
+	MetaAffinityField    = "*affinity_field"
+	MetaPercentages      = "*percentages"
+	MetaStrict           = "*strict"
+	MetaNormalize        = "*normalize"
+	MetaKillSwitch       = "*kill_switch"
+	MetaReject           = "*reject"
+)
+
 //Filter types
 const (
 	MetaNot            = "*not"
@@ -1897,6 +1917,9 @@ const (
 	// StatSCfg
 	StoreUncompressedLimitCfg = "store_uncompressed_limit"
 
+	// DispatcherSCfg
+	SelfHostIDCfg = "self_host_id"
+
 	// Cache
 	PartitionsCfg = "partitions"
 	PrecacheCfg   = "precache"
@@ -73,6 +73,8 @@ var (
 	ErrMaxIncrementsExceeded    = errors.New("MAX_INCREMENTS_EXCEEDED")
 	ErrIndexOutOfBounds         = errors.New("INDEX_OUT_OF_BOUNDS")
 	ErrWrongPath                = errors.New("WRONG_PATH")
+	ErrDispatcherDisabled       = errors.New("DISPATCHER_DISABLED")
+	ErrQuorumFailed             = errors.New("QUORUM_FAILED")
 
 	ErrMap = map[string]error{
 		ErrNoMoreData.Error():              ErrNoMoreData,
@@ -113,6 +115,8 @@ var (
 		ErrMaxIncrementsExceeded.Error():   ErrMaxIncrementsExceeded,
 		ErrIndexOutOfBounds.Error():        ErrIndexOutOfBounds,
 		ErrWrongPath.Error():               ErrWrongPath,
+		ErrDispatcherDisabled.Error():      ErrDispatcherDisabled,
+		ErrQuorumFailed.Error():            ErrQuorumFailed,
 	}
 )
 